@@ -31,6 +31,12 @@ func (a Array) Clone() Array {
 	return newArr
 }
 
+// MarshalJSON marshals the array as a JSON array. It is needed because Array also implements encoding.TextMarshaler,
+// which encoding/json would otherwise prefer over the default slice encoding.
+func (a Array) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]Value(a))
+}
+
 func (a *Array) UnmarshalJSON(data []byte) error {
 	s := string(data)
 	s = strings.TrimSpace(s)
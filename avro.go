@@ -0,0 +1,795 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AvroSchema is a parsed Avro schema that can encode Values into the Avro binary encoding and decode them back.
+// Records and maps are represented by Object, arrays by Array, enums and strings by String, bytes and fixed by Bytes,
+// and the timestamp-millis, timestamp-micros and date logical types by Time. A String is also accepted for bytes and
+// fixed when encoding.
+type AvroSchema struct {
+	schema Value
+	root   *avroType
+}
+
+// NewAvroSchema parses an Avro schema, given in its JSON form as a Value, e.g. the result of unmarshalling an .avsc
+// file
+func NewAvroSchema(schema Value) (*AvroSchema, error) {
+	p := avroSchemaParser{names: make(map[string]*avroType)}
+	root, err := p.parse(schema, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &AvroSchema{schema: schema.Clone(), root: root}, nil
+}
+
+// Schema returns the JSON form of the schema
+func (s *AvroSchema) Schema() Value {
+	return s.schema.Clone()
+}
+
+// Encode writes v in the Avro binary encoding
+func (s *AvroSchema) Encode(v Value) ([]byte, error) {
+	return s.root.encode(nil, v, "")
+}
+
+// Decode reads a single datum in the Avro binary encoding. All of data must be consumed.
+func (s *AvroSchema) Decode(data []byte) (Value, error) {
+	r := avroReader{data: data}
+	v, err := s.root.decode(&r)
+
+	if err != nil {
+		return Value{}, err
+	}
+
+	if !r.done() {
+		return Value{}, errors.New("avro: unexpected data after the end of the datum")
+	}
+
+	return v, nil
+}
+
+// WriteAvroContainer writes records to w as an Avro Object Container File with the null codec. All records are
+// written in a single block.
+func WriteAvroContainer(w io.Writer, schema *AvroSchema, records Array) error {
+	schemaJSON, err := json.Marshal(schema.schema)
+
+	if err != nil {
+		return err
+	}
+
+	var sync [16]byte
+
+	if _, err = rand.Read(sync[:]); err != nil {
+		return err
+	}
+
+	b := append([]byte{}, avroMagic...)
+	b = appendAvroLong(b, 2)
+	b = appendAvroBytes(b, []byte("avro.codec"))
+	b = appendAvroBytes(b, []byte("null"))
+	b = appendAvroBytes(b, []byte("avro.schema"))
+	b = appendAvroBytes(b, schemaJSON)
+	b = appendAvroLong(b, 0)
+	b = append(b, sync[:]...)
+
+	if len(records) > 0 {
+		var block []byte
+		for i, record := range records {
+			if block, err = schema.root.encode(block, record, ""); err != nil {
+				return fmt.Errorf("record %d: %s", i, err.Error())
+			}
+		}
+		b = appendAvroLong(b, int64(len(records)))
+		b = appendAvroBytes(b, block)
+		b = append(b, sync[:]...)
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+// ReadAvroContainer reads an Avro Object Container File, returning its schema and all of its records. The null and
+// deflate codecs are supported. A deflate block that decompresses to more than 64 MiB is an error.
+func ReadAvroContainer(r io.Reader) (*AvroSchema, Array, error) {
+	data, err := ioutil.ReadAll(r)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !bytes.HasPrefix(data, avroMagic) {
+		return nil, nil, errors.New("avro: this is not an object container file")
+	}
+
+	reader := avroReader{data: data, pos: len(avroMagic)}
+	metadata, err := avroMetadataType.decode(&reader)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sync, err := reader.read(16)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var schemaValue Value
+
+	if err = json.Unmarshal(metadata.Object()["avro.schema"].Bytes(), &schemaValue); err != nil {
+		return nil, nil, fmt.Errorf("avro: the container schema is invalid: %s", err.Error())
+	}
+
+	schema, err := NewAvroSchema(schemaValue)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	codec := string(metadata.Object()["avro.codec"].Bytes())
+
+	if codec != "" && codec != "null" && codec != "deflate" {
+		return nil, nil, fmt.Errorf("avro: the codec %q is not supported", codec)
+	}
+
+	records := NewArray()
+
+	for !reader.done() {
+		count, err := reader.long()
+		if err != nil {
+			return nil, nil, err
+		}
+		block, err := reader.bytes()
+		if err != nil {
+			return nil, nil, err
+		}
+		if codec == "deflate" {
+			inflated := io.LimitReader(flate.NewReader(bytes.NewReader(block)), avroMaxBlockSize+1)
+			if block, err = ioutil.ReadAll(inflated); err != nil {
+				return nil, nil, err
+			}
+			if len(block) > avroMaxBlockSize {
+				return nil, nil, fmt.Errorf("avro: a block decompresses to more than %d bytes", avroMaxBlockSize)
+			}
+		}
+		blockReader := avroReader{data: block, empty: reader.empty}
+		err = blockReader.items(count, func() error {
+			record, err := schema.root.decode(&blockReader)
+			if err != nil {
+				return err
+			}
+			records = append(records, record)
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		reader.empty = blockReader.empty
+		marker, err := reader.read(16)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !bytes.Equal(marker, sync) {
+			return nil, nil, errors.New("avro: the container has an invalid sync marker")
+		}
+	}
+
+	return schema, records, nil
+}
+
+// Private
+
+var avroMagic = []byte{'O', 'b', 'j', 1}
+
+// avroMetadataType is the schema of the container file header metadata, {"type": "map", "values": "bytes"}
+var avroMetadataType = &avroType{kind: "map", items: &avroType{kind: "bytes"}}
+
+type avroType struct {
+	kind     string
+	logical  string
+	name     string
+	fields   []avroField
+	symbols  []string
+	items    *avroType
+	size     int
+	branches []*avroType
+}
+
+type avroField struct {
+	name       string
+	typ        *avroType
+	def        Value
+	hasDefault bool
+}
+
+var avroPrimitives = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"int":     true,
+	"long":    true,
+	"float":   true,
+	"double":  true,
+	"bytes":   true,
+	"string":  true,
+}
+
+type avroSchemaParser struct {
+	names map[string]*avroType
+}
+
+func (p *avroSchemaParser) parse(schema Value, namespace string) (*avroType, error) {
+	switch schema.Type() {
+	case String:
+		name := schema.String()
+		if avroPrimitives[name] {
+			return &avroType{kind: name}, nil
+		}
+		if t, ok := p.names[avroFullName(name, namespace)]; ok {
+			return t, nil
+		} else if t, ok := p.names[name]; ok {
+			return t, nil
+		}
+		return nil, fmt.Errorf("avro: unknown type %q", name)
+	case ArrayType:
+		union := &avroType{kind: "union"}
+		for _, branch := range schema.Array() {
+			t, err := p.parse(branch, namespace)
+			if err != nil {
+				return nil, err
+			}
+			if t.kind == "union" {
+				return nil, errors.New("avro: unions may not immediately contain other unions")
+			}
+			union.branches = append(union.branches, t)
+		}
+		return union, nil
+	case ObjectType:
+		return p.parseObject(schema.Object(), namespace)
+	}
+
+	return nil, fmt.Errorf("avro: a schema cannot be a %s", schema.Type().String())
+}
+
+func (p *avroSchemaParser) parseObject(o Object, namespace string) (*avroType, error) {
+	typeValue := o["type"]
+
+	if !typeValue.IsString() {
+		return p.parse(typeValue, namespace)
+	}
+
+	t := &avroType{kind: typeValue.String(), logical: o["logicalType"].String()}
+
+	switch t.kind {
+	case "record", "error", "enum", "fixed":
+		if ns := o["namespace"].String(); ns != "" {
+			namespace = ns
+		}
+		name := o["name"].String()
+		if name == "" {
+			return nil, fmt.Errorf("avro: a %s must have a name", t.kind)
+		}
+		t.name = avroFullName(name, namespace)
+		if i := strings.LastIndex(t.name, "."); i >= 0 {
+			namespace = t.name[:i]
+		}
+		p.names[t.name] = t
+	}
+
+	switch t.kind {
+	case "record", "error":
+		t.kind = "record"
+		for _, fieldValue := range o["fields"].Array() {
+			fieldObject := fieldValue.Object()
+			fieldType, err := p.parse(fieldObject["type"], namespace)
+			if err != nil {
+				return nil, err
+			}
+			def, hasDefault := fieldObject["default"]
+			t.fields = append(t.fields, avroField{
+				name:       fieldObject["name"].String(),
+				typ:        fieldType,
+				def:        def,
+				hasDefault: hasDefault,
+			})
+		}
+	case "enum":
+		for _, symbol := range o["symbols"].Array() {
+			t.symbols = append(t.symbols, symbol.String())
+		}
+	case "array", "map":
+		itemsKey := "items"
+		if t.kind == "map" {
+			itemsKey = "values"
+		}
+		items, err := p.parse(o[itemsKey], namespace)
+		if err != nil {
+			return nil, err
+		}
+		t.items = items
+	case "fixed":
+		t.size = o["size"].Int()
+	default:
+		if !avroPrimitives[t.kind] {
+			return p.parse(typeValue, namespace)
+		}
+	}
+
+	return t, nil
+}
+
+func avroFullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+
+	return namespace + "." + name
+}
+
+func (t *avroType) describe() string {
+	if t.name != "" {
+		return t.name
+	}
+
+	return t.kind
+}
+
+func (t *avroType) mismatch(v Value, path string) error {
+	return fmt.Errorf("avro: cannot encode a %s as %s at %s", v.Type().String(), t.describe(), avroPath(path))
+}
+
+func avroPath(path string) string {
+	if path == "" {
+		return "the root"
+	}
+
+	return path
+}
+
+func (t *avroType) encode(b []byte, v Value, path string) ([]byte, error) {
+	switch t.kind {
+	case "null":
+		if !v.IsNull() {
+			return nil, t.mismatch(v, path)
+		}
+		return b, nil
+	case "boolean":
+		if !v.IsBool() {
+			return nil, t.mismatch(v, path)
+		}
+		if v.Bool() {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	case "int", "long":
+		if v.IsTime() && t.isTemporal() {
+			return appendAvroLong(b, t.fromTime(v.Time())), nil
+		} else if !v.IsInt() {
+			return nil, t.mismatch(v, path)
+		}
+		if t.kind == "int" && (v.Int() < math.MinInt32 || v.Int() > math.MaxInt32) {
+			return nil, fmt.Errorf("avro: %d overflows an int at %s", v.Int(), avroPath(path))
+		}
+		return appendAvroLong(b, int64(v.Int())), nil
+	case "float", "double":
+		f := v.Float()
+		if v.IsInt() {
+			f = float64(v.Int())
+		} else if !v.IsFloat() {
+			return nil, t.mismatch(v, path)
+		}
+		if t.kind == "float" {
+			var buf [4]byte
+			binary.LittleEndian.PutUint32(buf[:], math.Float32bits(float32(f)))
+			return append(b, buf[:]...), nil
+		}
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(f))
+		return append(b, buf[:]...), nil
+	case "string":
+		if !v.IsString() {
+			return nil, t.mismatch(v, path)
+		}
+		return appendAvroBytes(b, []byte(v.String())), nil
+	case "bytes":
+		if v.IsBytes() {
+			return appendAvroBytes(b, v.Bytes()), nil
+		} else if !v.IsString() {
+			return nil, t.mismatch(v, path)
+		}
+		return appendAvroBytes(b, []byte(v.String())), nil
+	case "fixed":
+		if !t.matches(v) {
+			return nil, t.mismatch(v, path)
+		}
+		if v.IsBytes() {
+			return append(b, v.Bytes()...), nil
+		}
+		return append(b, v.String()...), nil
+	case "enum":
+		if !v.IsString() {
+			return nil, t.mismatch(v, path)
+		}
+		for i, symbol := range t.symbols {
+			if v.String() == symbol {
+				return appendAvroLong(b, int64(i)), nil
+			}
+		}
+		return nil, fmt.Errorf("avro: %q is not a symbol of %s at %s", v.String(), t.describe(), avroPath(path))
+	case "array":
+		if v.Type() != ArrayType {
+			return nil, t.mismatch(v, path)
+		}
+		a := v.Array()
+		if len(a) > 0 {
+			b = appendAvroLong(b, int64(len(a)))
+		}
+		for i, item := range a {
+			var err error
+			if b, err = t.items.encode(b, item, fmt.Sprintf("%s/%d", path, i)); err != nil {
+				return nil, err
+			}
+		}
+		return appendAvroLong(b, 0), nil
+	case "map":
+		if v.Type() != ObjectType {
+			return nil, t.mismatch(v, path)
+		}
+		o := v.Object()
+		keys := make([]string, 0, len(o))
+		for key := range o {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		if len(keys) > 0 {
+			b = appendAvroLong(b, int64(len(keys)))
+		}
+		for _, key := range keys {
+			var err error
+			b = appendAvroBytes(b, []byte(key))
+			if b, err = t.items.encode(b, o[key], path+"/"+key); err != nil {
+				return nil, err
+			}
+		}
+		return appendAvroLong(b, 0), nil
+	case "record":
+		if v.Type() != ObjectType {
+			return nil, t.mismatch(v, path)
+		}
+		o := v.Object()
+		for _, field := range t.fields {
+			fieldValue, ok := o[field.name]
+			if !ok && field.hasDefault {
+				fieldValue = field.def
+			} else if !ok {
+				return nil, fmt.Errorf("avro: the required field %s/%s is missing", path, field.name)
+			}
+			var err error
+			if b, err = field.typ.encode(b, fieldValue, path+"/"+field.name); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case "union":
+		for i, branch := range t.branches {
+			if branch.matches(v) {
+				return branch.encode(appendAvroLong(b, int64(i)), v, path)
+			}
+		}
+		return nil, fmt.Errorf("avro: no branch of the union accepts a %s at %s", v.Type().String(), avroPath(path))
+	}
+
+	return nil, fmt.Errorf("avro: the type %s is not supported", t.kind)
+}
+
+// matches reports whether v can be encoded as t, and is used to choose the branch of a union
+func (t *avroType) matches(v Value) bool {
+	switch t.kind {
+	case "null":
+		return v.IsNull()
+	case "boolean":
+		return v.IsBool()
+	case "int", "long":
+		return v.IsInt() || (v.IsTime() && t.isTemporal())
+	case "float", "double":
+		return v.IsInt() || v.IsFloat()
+	case "string":
+		return v.IsString()
+	case "bytes":
+		return v.IsBytes() || v.IsString()
+	case "fixed":
+		return (v.IsBytes() && len(v.Bytes()) == t.size) || (v.IsString() && len(v.String()) == t.size)
+	case "enum":
+		for _, symbol := range t.symbols {
+			if v.IsString() && v.String() == symbol {
+				return true
+			}
+		}
+	case "array":
+		return v.Type() == ArrayType
+	case "map":
+		return v.Type() == ObjectType
+	case "record":
+		if v.Type() != ObjectType {
+			return false
+		}
+		for _, field := range t.fields {
+			if _, ok := v.Object()[field.name]; !ok && !field.hasDefault {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+func (t *avroType) isTemporal() bool {
+	switch t.logical {
+	case "timestamp-millis", "timestamp-micros":
+		return t.kind == "long"
+	case "date":
+		return t.kind == "int"
+	}
+
+	return false
+}
+
+func (t *avroType) fromTime(tm time.Time) int64 {
+	switch t.logical {
+	case "timestamp-micros":
+		return tm.Unix()*1000000 + int64(tm.Nanosecond()/1000)
+	case "date":
+		return int64(math.Floor(float64(tm.Unix()) / 86400))
+	}
+
+	return tm.Unix()*1000 + int64(tm.Nanosecond()/1000000)
+}
+
+func (t *avroType) toTime(i int64) time.Time {
+	switch t.logical {
+	case "timestamp-micros":
+		return time.Unix(i/1000000, (i%1000000)*1000).UTC()
+	case "date":
+		return time.Unix(i*86400, 0).UTC()
+	}
+
+	return time.Unix(i/1000, (i%1000)*1000000).UTC()
+}
+
+func (t *avroType) decode(r *avroReader) (Value, error) {
+	switch t.kind {
+	case "null":
+		return Value{}, nil
+	case "boolean":
+		b, err := r.read(1)
+		if err != nil {
+			return Value{}, err
+		}
+		return NewBoolValue(b[0] != 0), nil
+	case "int", "long":
+		i, err := r.long()
+		if err != nil {
+			return Value{}, err
+		}
+		if t.isTemporal() {
+			return NewTimeValue(t.toTime(i)), nil
+		}
+		return NewIntValue(int(i)), nil
+	case "float":
+		b, err := r.read(4)
+		if err != nil {
+			return Value{}, err
+		}
+		return NewFloatValue(float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))), nil
+	case "double":
+		b, err := r.read(8)
+		if err != nil {
+			return Value{}, err
+		}
+		return NewFloatValue(math.Float64frombits(binary.LittleEndian.Uint64(b))), nil
+	case "string":
+		b, err := r.bytes()
+		if err != nil {
+			return Value{}, err
+		}
+		return NewStringValue(string(b)), nil
+	case "bytes":
+		b, err := r.bytes()
+		if err != nil {
+			return Value{}, err
+		}
+		return NewBytesValue(append([]byte{}, b...)), nil
+	case "fixed":
+		b, err := r.read(t.size)
+		if err != nil {
+			return Value{}, err
+		}
+		return NewBytesValue(append([]byte{}, b...)), nil
+	case "enum":
+		i, err := r.long()
+		if err != nil {
+			return Value{}, err
+		}
+		if i < 0 || i >= int64(len(t.symbols)) {
+			return Value{}, fmt.Errorf("avro: the enum index %d is out of range for %s", i, t.describe())
+		}
+		return NewStringValue(t.symbols[i]), nil
+	case "array":
+		a := NewArray()
+		err := r.blocks(func() error {
+			item, err := t.items.decode(r)
+			a = append(a, item)
+			return err
+		})
+		return NewArrayValue(a), err
+	case "map":
+		o := NewObject(3)
+		err := r.blocks(func() error {
+			key, err := r.bytes()
+			if err != nil {
+				return err
+			}
+			item, err := t.items.decode(r)
+			o[string(key)] = item
+			return err
+		})
+		return NewObjectValue(o), err
+	case "record":
+		o := NewObject(len(t.fields))
+		for _, field := range t.fields {
+			fieldValue, err := field.typ.decode(r)
+			if err != nil {
+				return Value{}, err
+			}
+			o[field.name] = fieldValue
+		}
+		return NewObjectValue(o), nil
+	case "union":
+		i, err := r.long()
+		if err != nil {
+			return Value{}, err
+		}
+		if i < 0 || i >= int64(len(t.branches)) {
+			return Value{}, fmt.Errorf("avro: the union index %d is out of range", i)
+		}
+		return t.branches[i].decode(r)
+	}
+
+	return Value{}, fmt.Errorf("avro: the type %s is not supported", t.kind)
+}
+
+func appendAvroLong(b []byte, i int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], i)
+	return append(b, buf[:n]...)
+}
+
+func appendAvroBytes(b []byte, data []byte) []byte {
+	b = appendAvroLong(b, int64(len(data)))
+	return append(b, data...)
+}
+
+var errAvroTruncated = errors.New("avro: the data is truncated")
+
+// avroMaxBlockSize is the most that a single compressed container block may decompress to
+const avroMaxBlockSize = 64 << 20
+
+// avroMaxEmptyItems caps how many items that take up no bytes, e.g. nulls or empty records, a single read may
+// produce. Every other item uses up at least one byte, so a count from the input can be no larger than the bytes left
+// plus this cap.
+const avroMaxEmptyItems = 1 << 16
+
+// avroReader reads the Avro binary encoding
+type avroReader struct {
+	data  []byte
+	pos   int
+	empty int
+}
+
+func (r *avroReader) done() bool {
+	return r.pos >= len(r.data)
+}
+
+func (r *avroReader) long() (int64, error) {
+	i, n := binary.Varint(r.data[r.pos:])
+
+	if n <= 0 {
+		return 0, errAvroTruncated
+	}
+
+	r.pos += n
+	return i, nil
+}
+
+func (r *avroReader) read(n int) ([]byte, error) {
+	if n < 0 || n > len(r.data)-r.pos {
+		return nil, errAvroTruncated
+	}
+
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *avroReader) bytes() ([]byte, error) {
+	n, err := r.long()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if n > int64(len(r.data)-r.pos) {
+		return nil, errAvroTruncated
+	}
+
+	return r.read(int(n))
+}
+
+// blocks reads the blocks of an array or map, calling item once for each item
+func (r *avroReader) blocks(item func() error) error {
+	for {
+		count, err := r.long()
+
+		if err != nil {
+			return err
+		}
+
+		if count == 0 {
+			return nil
+		}
+
+		if count < 0 {
+			// a negative count is followed by the size of the block in bytes, which we do not need
+			count = -count
+			if _, err = r.long(); err != nil {
+				return err
+			}
+		}
+
+		if err = r.items(count, item); err != nil {
+			return err
+		}
+	}
+}
+
+// items calls item count times, where count was read from the input. A negative count, or one larger than the input
+// could possibly hold, is an error, as is producing more than avroMaxEmptyItems items that take up no bytes.
+func (r *avroReader) items(count int64, item func() error) error {
+	if count < 0 || count > int64(len(r.data)-r.pos)+avroMaxEmptyItems {
+		return fmt.Errorf("avro: the count %d is out of range", count)
+	}
+
+	for i := int64(0); i < count; i++ {
+		pos := r.pos
+
+		if err := item(); err != nil {
+			return err
+		}
+
+		if r.pos == pos {
+			if r.empty++; r.empty > avroMaxEmptyItems {
+				return fmt.Errorf("avro: more than %d items take up no bytes", avroMaxEmptyItems)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/webern/tcore"
+)
+
+const testAvroSchema = `{
+	"type": "record",
+	"name": "User",
+	"namespace": "example",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "name", "type": "string"},
+		{"name": "nickname", "type": ["null", "string"], "default": null},
+		{"name": "tags", "type": {"type": "array", "items": "string"}},
+		{"name": "scores", "type": {"type": "map", "values": "double"}},
+		{"name": "role", "type": {"type": "enum", "name": "Role", "symbols": ["ADMIN", "USER"]}},
+		{"name": "created", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "manager", "type": ["null", "User"], "default": null}
+	]
+}`
+
+func newTestAvroSchema(t *testing.T) *AvroSchema {
+	var schemaValue Value
+
+	if err := json.Unmarshal([]byte(testAvroSchema), &schemaValue); err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := NewAvroSchema(schemaValue)
+
+	if msg, ok := tcore.TErr("NewAvroSchema", err); !ok {
+		t.Fatal(msg)
+	}
+
+	return schema
+}
+
+func newTestAvroUser(id int, manager Value) Value {
+	return NewObjectValue(Object{
+		"id":       NewIntValue(id),
+		"name":     NewStringValue(fmt.Sprintf("user %d", id)),
+		"nickname": NewStringValue("nick"),
+		"tags":     NewArrayValue(Array{NewStringValue("a"), NewStringValue("b")}),
+		"scores":   NewObjectValue(Object{"math": NewFloatValue(0.5)}),
+		"role":     NewStringValue("USER"),
+		"created":  NewTimeValue(time.Date(2019, 5, 6, 10, 0, 0, 123000000, time.UTC)),
+		"manager":  manager,
+	})
+}
+
+func TestAvroSchema_EncodePrimitives(t *testing.T) {
+	nullOrInt := NewArrayValue(Array{NewStringValue("null"), NewStringValue("int")})
+
+	testCases := []struct {
+		Schema   Value
+		Input    Value
+		Expected []byte
+	}{
+		{Schema: NewStringValue("int"), Input: NewIntValue(1), Expected: []byte{0x02}},
+		{Schema: NewStringValue("long"), Input: NewIntValue(-64), Expected: []byte{0x7f}},
+		{Schema: NewStringValue("string"), Input: NewStringValue("foo"), Expected: []byte{0x06, 'f', 'o', 'o'}},
+		{Schema: NewStringValue("boolean"), Input: NewBoolValue(true), Expected: []byte{0x01}},
+		{Schema: NewStringValue("bytes"), Input: NewBytesValue([]byte{0xff, 0}), Expected: []byte{0x04, 0xff, 0}},
+		{Schema: NewStringValue("bytes"), Input: NewStringValue("a"), Expected: []byte{0x02, 'a'}},
+		{Schema: nullOrInt, Input: NewValue(), Expected: []byte{0x00}},
+		{Schema: nullOrInt, Input: NewIntValue(3), Expected: []byte{0x02, 0x06}},
+	}
+
+	for tcix, tc := range testCases {
+		schema, err := NewAvroSchema(tc.Schema)
+
+		if msg, ok := tcore.TErr(fmt.Sprintf("test case %d: NewAvroSchema", tcix), err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		actual, err := schema.Encode(tc.Input)
+
+		if msg, ok := tcore.TErr(fmt.Sprintf("test case %d: schema.Encode", tcix), err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		if !bytes.Equal(actual, tc.Expected) {
+			t.Errorf("test case %d: schema.Encode = % x, want % x", tcix, actual, tc.Expected)
+		}
+	}
+}
+
+func TestAvroSchema_RoundTrip(t *testing.T) {
+	schema := newTestAvroSchema(t)
+	user := newTestAvroUser(2, newTestAvroUser(1, NewValue()))
+	b, err := schema.Encode(user)
+
+	if msg, ok := tcore.TErr("schema.Encode", err); !ok {
+		t.Fatal(msg)
+	}
+
+	actual, err := schema.Decode(b)
+
+	if msg, ok := tcore.TErr("schema.Decode", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("actual.Equals(user)", actual.Equals(user), true); !ok {
+		t.Error(msg + fmt.Sprintf(" - '%v' != '%v'", actual, user))
+	}
+
+	wrong := newTestAvroUser(3, NewValue())
+	wrong.Object()["role"] = NewStringValue("GUEST")
+
+	if _, err = schema.Encode(wrong); err == nil {
+		t.Error("an error was expected for an unknown enum symbol")
+	}
+
+	wrong.Object()["role"] = NewIntValue(1)
+	_, err = schema.Encode(wrong)
+
+	if err == nil || !strings.Contains(err.Error(), "cannot encode a VALUE_INTEGER as example.Role") {
+		t.Errorf("expected an error naming the Type of a non-string enum value but got '%v'", err)
+	}
+}
+
+func TestAvroContainer(t *testing.T) {
+	schema := newTestAvroSchema(t)
+	records := Array{newTestAvroUser(1, NewValue()), newTestAvroUser(2, NewValue())}
+	buf := bytes.Buffer{}
+	err := WriteAvroContainer(&buf, schema, records)
+
+	if msg, ok := tcore.TErr("WriteAvroContainer", err); !ok {
+		t.Fatal(msg)
+	}
+
+	readSchema, readRecords, err := ReadAvroContainer(&buf)
+
+	if msg, ok := tcore.TErr("ReadAvroContainer", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("ArraysEqual(readRecords, records)", ArraysEqual(readRecords, records), true); !ok {
+		t.Error(msg)
+	}
+
+	schemaValue := readSchema.Schema()
+
+	stm := "schemaValue.Equals(schema.Schema())"
+
+	if msg, ok := tcore.TAssertBool(stm, schemaValue.Equals(schema.Schema()), true); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestAvroSchema_DecodeHostileCounts(t *testing.T) {
+	schema, err := NewAvroSchema(NewObjectValue(Object{
+		"type":  NewStringValue("array"),
+		"items": NewStringValue("null"),
+	}))
+
+	if msg, ok := tcore.TErr("NewAvroSchema", err); !ok {
+		t.Fatal(msg)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"huge", append(appendAvroLong(nil, 1<<26), 0)},
+		{"negative", append(appendAvroLong(appendAvroLong(nil, math.MinInt64), 0), 0)},
+		{"many blocks", bytes.Repeat(appendAvroLong(nil, avroMaxEmptyItems), 2)},
+	}
+
+	for _, test := range tests {
+		_, err := schema.Decode(test.data)
+
+		if msg, ok := tcore.TAssertBool(test.name+" err != nil", err != nil, true); !ok {
+			t.Error(msg)
+		}
+	}
+
+	v, err := schema.Decode(append(appendAvroLong(nil, 3), 0))
+
+	if msg, ok := tcore.TErr("Decode", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertInt("len(v.Array())", len(v.Array()), 3); !ok {
+		t.Error(msg)
+	}
+}
+
+// newTestAvroContainer builds a container for the "null" schema holding a single block, without checking any of it
+func newTestAvroContainer(codec string, count int64, block []byte) []byte {
+	sync := bytes.Repeat([]byte{7}, 16)
+	b := append([]byte{}, avroMagic...)
+	b = appendAvroLong(b, 2)
+	b = appendAvroBytes(b, []byte("avro.codec"))
+	b = appendAvroBytes(b, []byte(codec))
+	b = appendAvroBytes(b, []byte("avro.schema"))
+	b = appendAvroBytes(b, []byte(`"null"`))
+	b = appendAvroLong(b, 0)
+	b = append(b, sync...)
+	b = appendAvroLong(b, count)
+	b = appendAvroBytes(b, block)
+	return append(b, sync...)
+}
+
+func TestReadAvroContainer_HostileRecordCount(t *testing.T) {
+	_, _, err := ReadAvroContainer(bytes.NewReader(newTestAvroContainer("null", 1<<40, nil)))
+
+	if msg, ok := tcore.TAssertBool("err != nil", err != nil, true); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestReadAvroContainer_DeflateBomb(t *testing.T) {
+	buf := bytes.Buffer{}
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = w.Write(make([]byte, avroMaxBlockSize+1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = ReadAvroContainer(bytes.NewReader(newTestAvroContainer("deflate", 1, buf.Bytes())))
+
+	if msg, ok := tcore.TAssertBool("err != nil", err != nil, true); !ok {
+		t.Error(msg)
+	}
+}
@@ -0,0 +1,420 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// MarshalBinary encodes the Value in a compact binary format that preserves the exact Type of every node, including
+// Time, which does not survive a round trip through JSON. It implements encoding.BinaryMarshaler.
+func (v Value) MarshalBinary() ([]byte, error) {
+	return appendBinaryValue([]byte{binaryVersion}, v)
+}
+
+// UnmarshalBinary decodes data produced by MarshalBinary. It implements encoding.BinaryUnmarshaler.
+func (v *Value) UnmarshalBinary(data []byte) error {
+	decoded, err := decodeBinary(data)
+
+	if err != nil {
+		return err
+	}
+
+	*v = decoded
+	return nil
+}
+
+// MarshalText encodes the Value as the base64 text of its binary encoding. It implements encoding.TextMarshaler, so
+// any encoder that prefers that interface, such as most YAML, TOML and XML libraries, writes a Value as one opaque
+// base64 string rather than as its structure. encoding/json is not affected because it prefers MarshalJSON.
+func (v Value) MarshalText() ([]byte, error) {
+	return marshalBinaryText(v)
+}
+
+// UnmarshalText decodes text produced by MarshalText. It implements encoding.TextUnmarshaler.
+func (v *Value) UnmarshalText(text []byte) error {
+	data, err := unmarshalBinaryText(text)
+
+	if err != nil {
+		return err
+	}
+
+	return v.UnmarshalBinary(data)
+}
+
+// GobEncode implements gob.GobEncoder using the binary encoding
+func (v Value) GobEncode() ([]byte, error) {
+	return v.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder using the binary encoding
+func (v *Value) GobDecode(data []byte) error {
+	return v.UnmarshalBinary(data)
+}
+
+// MarshalBinary encodes the Object using the same format as Value.MarshalBinary
+func (o Object) MarshalBinary() ([]byte, error) {
+	return NewObjectValue(o).MarshalBinary()
+}
+
+// UnmarshalBinary decodes data produced by Object.MarshalBinary
+func (o *Object) UnmarshalBinary(data []byte) error {
+	v, err := decodeBinary(data)
+
+	if err != nil {
+		return err
+	}
+
+	if v.Type() != ObjectType {
+		return fmt.Errorf("the binary data holds a %s, not an object", v.Type().String())
+	}
+
+	*o = v.Object()
+	return nil
+}
+
+// MarshalText encodes the Object as the base64 text of its binary encoding. As with Value.MarshalText, encoders
+// driven by encoding.TextMarshaler write an Object as opaque base64, while encoding/json uses MarshalJSON.
+func (o Object) MarshalText() ([]byte, error) {
+	return marshalBinaryText(NewObjectValue(o))
+}
+
+// UnmarshalText decodes text produced by Object.MarshalText
+func (o *Object) UnmarshalText(text []byte) error {
+	data, err := unmarshalBinaryText(text)
+
+	if err != nil {
+		return err
+	}
+
+	return o.UnmarshalBinary(data)
+}
+
+// GobEncode implements gob.GobEncoder using the binary encoding
+func (o Object) GobEncode() ([]byte, error) {
+	return o.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder using the binary encoding
+func (o *Object) GobDecode(data []byte) error {
+	return o.UnmarshalBinary(data)
+}
+
+// MarshalBinary encodes the Array using the same format as Value.MarshalBinary
+func (a Array) MarshalBinary() ([]byte, error) {
+	return NewArrayValue(a).MarshalBinary()
+}
+
+// UnmarshalBinary decodes data produced by Array.MarshalBinary
+func (a *Array) UnmarshalBinary(data []byte) error {
+	v, err := decodeBinary(data)
+
+	if err != nil {
+		return err
+	}
+
+	if v.Type() != ArrayType {
+		return fmt.Errorf("the binary data holds a %s, not an array", v.Type().String())
+	}
+
+	*a = v.Array()
+	return nil
+}
+
+// MarshalText encodes the Array as the base64 text of its binary encoding. As with Value.MarshalText, encoders
+// driven by encoding.TextMarshaler write an Array as opaque base64, while encoding/json uses MarshalJSON.
+func (a Array) MarshalText() ([]byte, error) {
+	return marshalBinaryText(NewArrayValue(a))
+}
+
+// UnmarshalText decodes text produced by Array.MarshalText
+func (a *Array) UnmarshalText(text []byte) error {
+	data, err := unmarshalBinaryText(text)
+
+	if err != nil {
+		return err
+	}
+
+	return a.UnmarshalBinary(data)
+}
+
+// GobEncode implements gob.GobEncoder using the binary encoding
+func (a Array) GobEncode() ([]byte, error) {
+	return a.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder using the binary encoding
+func (a *Array) GobDecode(data []byte) error {
+	return a.UnmarshalBinary(data)
+}
+
+// Private
+
+// binaryVersion is the first byte of the binary encoding so that the format can change in the future
+const binaryVersion = 1
+
+// binaryMaxDepth limits how deeply Objects and Arrays may nest in decoded data, as encoding/json does, so that hostile
+// input cannot exhaust the stack
+const binaryMaxDepth = 10000
+
+var errBinaryTruncated = errors.New("the binary data is truncated")
+
+func marshalBinaryText(v Value) ([]byte, error) {
+	data, err := v.MarshalBinary()
+
+	if err != nil {
+		return nil, err
+	}
+
+	text := make([]byte, base64.StdEncoding.EncodedLen(len(data)))
+	base64.StdEncoding.Encode(text, data)
+	return text, nil
+}
+
+func unmarshalBinaryText(text []byte) ([]byte, error) {
+	data := make([]byte, base64.StdEncoding.DecodedLen(len(text)))
+	n, err := base64.StdEncoding.Decode(data, text)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data[:n], nil
+}
+
+// appendBinaryValue writes the Type of v as a single byte followed by its contents
+func appendBinaryValue(b []byte, v Value) ([]byte, error) {
+	t := v.Type()
+	b = append(b, byte(t))
+
+	switch t {
+	case Null:
+		return b, nil
+	case Bool:
+		if v.Bool() {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	case Int:
+		var buf [binary.MaxVarintLen64]byte
+		n := binary.PutVarint(buf[:], int64(v.Int()))
+		return append(b, buf[:n]...), nil
+	case Float:
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], math.Float64bits(v.Float()))
+		return append(b, buf[:]...), nil
+	case String:
+		return appendBinaryString(b, v.String()), nil
+	case Bytes:
+		return appendBinaryString(b, string(v.Bytes())), nil
+	case Time:
+		tb, err := v.Time().MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return appendBinaryString(b, string(tb)), nil
+	case ObjectType:
+		o := v.Object()
+		keys := make([]string, 0, len(o))
+		for key := range o {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		b = appendUvarint(b, uint64(len(keys)))
+		for _, key := range keys {
+			var err error
+			b = appendBinaryString(b, key)
+			if b, err = appendBinaryValue(b, o[key]); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case ArrayType:
+		a := v.Array()
+		b = appendUvarint(b, uint64(len(a)))
+		for _, element := range a {
+			var err error
+			if b, err = appendBinaryValue(b, element); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	}
+
+	return nil, fmt.Errorf("the type %s cannot be encoded", t.String())
+}
+
+func appendBinaryString(b []byte, s string) []byte {
+	b = appendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func decodeBinary(data []byte) (Value, error) {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return Value{}, errors.New("the binary data is not an encoded Value")
+	}
+
+	r := binaryReader{data: data, pos: 1}
+	v, err := r.value()
+
+	if err != nil {
+		return Value{}, err
+	}
+
+	if r.pos != len(data) {
+		return Value{}, errors.New("unexpected data after the end of the encoded Value")
+	}
+
+	return v, nil
+}
+
+// binaryReader reads the format written by appendBinaryValue
+type binaryReader struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+func (r *binaryReader) read(n int) ([]byte, error) {
+	if n < 0 || n > len(r.data)-r.pos {
+		return nil, errBinaryTruncated
+	}
+
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *binaryReader) uvarint() (uint64, error) {
+	u, n := binary.Uvarint(r.data[r.pos:])
+
+	if n <= 0 {
+		return 0, errBinaryTruncated
+	}
+
+	r.pos += n
+	return u, nil
+}
+
+func (r *binaryReader) string() (string, error) {
+	n, err := r.uvarint()
+
+	if err != nil {
+		return "", err
+	}
+
+	if n > uint64(len(r.data)-r.pos) {
+		return "", errBinaryTruncated
+	}
+
+	b, err := r.read(int(n))
+	return string(b), err
+}
+
+func (r *binaryReader) value() (Value, error) {
+	var v Value
+	tb, err := r.read(1)
+
+	if err != nil {
+		return v, err
+	}
+
+	if t := Type(tb[0]); t == ObjectType || t == ArrayType {
+		if r.depth++; r.depth > binaryMaxDepth {
+			return v, fmt.Errorf("the binary data nests deeper than %d levels", binaryMaxDepth)
+		}
+		defer func() { r.depth-- }()
+	}
+
+	switch t := Type(tb[0]); t {
+	case Null:
+		return v, nil
+	case Bool:
+		b, err := r.read(1)
+		if err != nil {
+			return v, err
+		}
+		v.SetBool(b[0] != 0)
+	case Int:
+		i, n := binary.Varint(r.data[r.pos:])
+		if n <= 0 {
+			return v, errBinaryTruncated
+		}
+		r.pos += n
+		v.SetInt(int(i))
+	case Float:
+		b, err := r.read(8)
+		if err != nil {
+			return v, err
+		}
+		v.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(b)))
+	case String:
+		s, err := r.string()
+		if err != nil {
+			return v, err
+		}
+		v.SetString(s)
+	case Bytes:
+		s, err := r.string()
+		if err != nil {
+			return v, err
+		}
+		v.SetBytes([]byte(s))
+	case Time:
+		s, err := r.string()
+		if err != nil {
+			return v, err
+		}
+		var tm time.Time
+		if err = tm.UnmarshalBinary([]byte(s)); err != nil {
+			return v, err
+		}
+		v.SetTime(tm)
+	case ObjectType:
+		count, err := r.uvarint()
+		if err != nil {
+			return v, err
+		}
+		if count > uint64(len(r.data)-r.pos) {
+			return v, errBinaryTruncated
+		}
+		o := NewObject(int(count))
+		for i := uint64(0); i < count; i++ {
+			key, err := r.string()
+			if err != nil {
+				return v, err
+			}
+			if o[key], err = r.value(); err != nil {
+				return v, err
+			}
+		}
+		v.SetObject(o)
+	case ArrayType:
+		count, err := r.uvarint()
+		if err != nil {
+			return v, err
+		}
+		if count > uint64(len(r.data)-r.pos) {
+			return v, errBinaryTruncated
+		}
+		a := make(Array, 0, int(count))
+		for i := uint64(0); i < count; i++ {
+			element, err := r.value()
+			if err != nil {
+				return v, err
+			}
+			a = append(a, element)
+		}
+		v.SetArray(a)
+	default:
+		return v, fmt.Errorf("the binary data contains the unknown type %d", t)
+	}
+
+	return v, nil
+}
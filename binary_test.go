@@ -0,0 +1,176 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/webern/tcore"
+)
+
+func newTestBinaryValue() Value {
+	zone := time.FixedZone("test", -7*60*60)
+
+	return NewObjectValue(Object{
+		"null":   NewValue(),
+		"bool":   NewBoolValue(true),
+		"int":    NewIntValue(-12345),
+		"float":  NewFloatValue(1.0),
+		"string": NewStringValue("hello"),
+		"bytes":  NewBytesValue([]byte{0, 1, 0xff}),
+		"time":   NewTimeValue(time.Date(2019, 5, 6, 10, 0, 0, 123456789, zone)),
+		"array":  NewArrayValue(Array{NewIntValue(1), NewArrayValue(NewArray()), NewObjectValue(nil)}),
+	})
+}
+
+// assertSameTypes checks that Equals holds and that every node kept its exact Type and time
+func assertSameTypes(t *testing.T, stm string, actual, expected Value) {
+	if msg, ok := tcore.TAssertBool(stm+".Equals(expected)", actual.Equals(expected), true); !ok {
+		t.Error(msg + fmt.Sprintf(" - '%v' != '%v'", actual, expected))
+		return
+	}
+
+	o := actual.Object()
+	gotF := o["float"].Type().String()
+
+	if msg, ok := tcore.TAssertString(stm+" float type", gotF, Float.String()); !ok {
+		t.Error(msg)
+	}
+
+	got, want := o["time"].Time(), expected.Object()["time"].Time()
+
+	if !got.Equal(want) || got.Format(time.RFC3339Nano) != want.Format(time.RFC3339Nano) {
+		t.Errorf("%s time = %v, want %v", stm, got, want)
+	}
+}
+
+func TestValue_MarshalBinary(t *testing.T) {
+	expected := newTestBinaryValue()
+	b, err := expected.MarshalBinary()
+
+	if msg, ok := tcore.TErr("expected.MarshalBinary()", err); !ok {
+		t.Fatal(msg)
+	}
+
+	var actual Value
+	err = actual.UnmarshalBinary(b)
+
+	if msg, ok := tcore.TErr("actual.UnmarshalBinary(b)", err); !ok {
+		t.Fatal(msg)
+	}
+
+	assertSameTypes(t, "binary", actual, expected)
+
+	if err = actual.UnmarshalBinary(b[:len(b)-1]); err == nil {
+		t.Error("an error was expected for truncated data")
+	}
+}
+
+func TestValue_MarshalText(t *testing.T) {
+	expected := newTestBinaryValue()
+	text, err := expected.MarshalText()
+
+	if msg, ok := tcore.TErr("expected.MarshalText()", err); !ok {
+		t.Fatal(msg)
+	}
+
+	var actual Value
+	err = actual.UnmarshalText(text)
+
+	if msg, ok := tcore.TErr("actual.UnmarshalText(text)", err); !ok {
+		t.Fatal(msg)
+	}
+
+	assertSameTypes(t, "text", actual, expected)
+
+	var o Object
+	err = o.UnmarshalText(text)
+
+	if msg, ok := tcore.TErr("o.UnmarshalText(text)", err); !ok {
+		t.Fatal(msg)
+	}
+
+	assertSameTypes(t, "o", NewObjectValue(o), expected)
+
+	var a Array
+	if err = a.UnmarshalText(text); err == nil {
+		t.Error("an error was expected when unmarshalling an object into an Array")
+	}
+
+	if err = actual.UnmarshalText([]byte("not base64!")); err == nil {
+		t.Error("an error was expected for text that is not base64")
+	}
+}
+
+func TestValue_Gob(t *testing.T) {
+	type Envelope struct {
+		V Value
+		O Object
+		A Array
+	}
+
+	expected := newTestBinaryValue()
+	in := Envelope{V: expected, O: expected.Object(), A: Array{expected}}
+	buf := bytes.Buffer{}
+
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+
+	var out Envelope
+
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	assertSameTypes(t, "out.V", out.V, expected)
+	assertSameTypes(t, "out.O", NewObjectValue(out.O), expected)
+	assertSameTypes(t, "out.A[0]", out.A[0], expected)
+}
+
+func TestObject_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(Object{"a": NewArrayValue(Array{NewIntValue(1)})})
+
+	if msg, ok := tcore.TErr("json.Marshal", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertString("json.Marshal", string(b), `{"a":[1]}`); !ok {
+		t.Error(msg)
+	}
+
+	b, err = json.Marshal(Array{NewObjectValue(Object{"b": NewBoolValue(true)})})
+
+	if msg, ok := tcore.TErr("json.Marshal", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertString("json.Marshal", string(b), `[{"b":true}]`); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestValue_UnmarshalBinaryDepth(t *testing.T) {
+	nested := func(depth int) []byte {
+		b := []byte{binaryVersion}
+		for i := 0; i < depth; i++ {
+			b = append(b, byte(ArrayType), 1)
+		}
+		return append(b, byte(Null))
+	}
+
+	var v Value
+
+	if err := v.UnmarshalBinary(nested(binaryMaxDepth)); err != nil {
+		t.Errorf("UnmarshalBinary at the maximum depth: %s", err.Error())
+	}
+
+	if err := v.UnmarshalBinary(nested(binaryMaxDepth + 1)); err == nil {
+		t.Error("an error was expected for data nested past the maximum depth")
+	}
+}
@@ -0,0 +1,230 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Compare returns -1, 0 or 1 as a sorts before, equal to or after b. Values of different kinds sort in the order
+// Null < Bool < numbers < String < Bytes < Time < Array < Object, with false before true.
+//
+// Compare orders by value and does not follow Equals: Int 1 and Float 1.0 compare as equal although Equals treats them
+// as different, and Times compare at full nanosecond precision although Equals only compares them to the second. Int
+// and Float compare numerically and exactly, without rounding large Ints, and NaN sorts before every other number and
+// equal to itself. Strings and Bytes compare bytewise. Arrays compare element by element, and Objects compare their
+// members in sorted key order, key first, then value; in both a shorter prefix sorts first.
+func Compare(a, b Value) int {
+	if ra, rb := compareRank(a.Type()), compareRank(b.Type()); ra != rb {
+		return compareInts(ra, rb)
+	}
+
+	switch a.Type() {
+	case Null:
+		return 0
+	case Bool:
+		if a.Bool() == b.Bool() {
+			return 0
+		} else if b.Bool() {
+			return -1
+		}
+		return 1
+	case Int, Float:
+		return compareNumbers(a, b)
+	case String:
+		return strings.Compare(a.String(), b.String())
+	case Bytes:
+		return bytes.Compare(a.Bytes(), b.Bytes())
+	case Time:
+		if a.Time().Before(b.Time()) {
+			return -1
+		} else if a.Time().After(b.Time()) {
+			return 1
+		}
+		return 0
+	case ArrayType:
+		return compareArrays(a.Array(), b.Array())
+	case ObjectType:
+		return compareObjects(a.Object(), b.Object())
+	}
+
+	return 0
+}
+
+// Sort sorts the Array in place in the order defined by Compare
+func (a Array) Sort() {
+	sort.Slice(a, func(i, j int) bool { return Compare(a[i], a[j]) < 0 })
+}
+
+// SortStable is Sort, but it keeps equal elements in their original order
+func (a Array) SortStable() {
+	sort.SliceStable(a, func(i, j int) bool { return Compare(a[i], a[j]) < 0 })
+}
+
+// SortBy sorts the Array in place by the Value that a JSON Pointer refers to within each element, e.g. /name. Elements
+// in which the pointer refers to nothing sort first.
+func (a Array) SortBy(pointer string) error {
+	less, err := sortByLess(a, pointer)
+
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(a, less)
+	return nil
+}
+
+// SortStableBy is SortBy, but it keeps equal elements in their original order
+func (a Array) SortStableBy(pointer string) error {
+	less, err := sortByLess(a, pointer)
+
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(a, less)
+	return nil
+}
+
+// Private
+
+func compareRank(t Type) int {
+	switch t {
+	case Null:
+		return 0
+	case Bool:
+		return 1
+	case Int, Float:
+		return 2
+	case String:
+		return 3
+	case Bytes:
+		return 4
+	case Time:
+		return 5
+	case ArrayType:
+		return 6
+	}
+
+	return 7
+}
+
+func compareInts(a, b int) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+
+	return 0
+}
+
+func compareNumbers(a, b Value) int {
+	switch {
+	case a.IsInt() && b.IsInt():
+		return compareInts(a.Int(), b.Int())
+	case a.IsFloat() && b.IsFloat():
+		return compareFloats(a.Float(), b.Float())
+	case a.IsInt():
+		return compareIntFloat(a.Int(), b.Float())
+	}
+
+	return -compareIntFloat(b.Int(), a.Float())
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case math.IsNaN(a) && math.IsNaN(b):
+		return 0
+	case math.IsNaN(a):
+		return -1
+	case math.IsNaN(b):
+		return 1
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+// compareIntFloat compares exactly, without rounding large Ints to the nearest float64
+func compareIntFloat(i int, f float64) int {
+	switch {
+	case math.IsNaN(f):
+		return 1
+	case f >= math.MaxInt64:
+		return -1
+	case f < math.MinInt64:
+		return 1
+	}
+
+	truncated := math.Trunc(f)
+
+	if c := compareInts64(int64(i), int64(truncated)); c != 0 {
+		return c
+	}
+
+	return compareFloats(truncated, f)
+}
+
+func compareInts64(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+
+	return 0
+}
+
+func compareArrays(a, b Array) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := Compare(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+
+	return compareInts(len(a), len(b))
+}
+
+func compareObjects(a, b Object) int {
+	aKeys, bKeys := sortedKeys(a), sortedKeys(b)
+
+	for i := 0; i < len(aKeys) && i < len(bKeys); i++ {
+		if c := strings.Compare(aKeys[i], bKeys[i]); c != 0 {
+			return c
+		}
+		if c := Compare(a[aKeys[i]], b[bKeys[i]]); c != 0 {
+			return c
+		}
+	}
+
+	return compareInts(len(aKeys), len(bKeys))
+}
+
+func sortByLess(a Array, pointer string) (func(i, j int) bool, error) {
+	path, err := ParsePointer(pointer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func(i, j int) bool {
+		x, xErr := getPath(a[i], pointer, path)
+		y, yErr := getPath(a[j], pointer, path)
+
+		switch {
+		case xErr != nil:
+			return yErr == nil
+		case yErr != nil:
+			return false
+		}
+
+		return Compare(x, y) < 0
+	}, nil
+}
@@ -0,0 +1,107 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/webern/tcore"
+)
+
+func TestCompare(t *testing.T) {
+	t0 := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		A        Value
+		B        Value
+		Expected int
+	}{
+		{A: NewValue(), B: NewValue(), Expected: 0},
+		{A: NewValue(), B: NewBoolValue(false), Expected: -1},
+		{A: NewBoolValue(false), B: NewBoolValue(true), Expected: -1},
+		{A: NewBoolValue(true), B: NewIntValue(-5), Expected: -1},
+		{A: NewIntValue(2), B: NewIntValue(10), Expected: -1},
+		{A: NewIntValue(2), B: NewFloatValue(1.5), Expected: 1},
+		{A: NewFloatValue(2.5), B: NewIntValue(3), Expected: -1},
+		{A: NewIntValue(-2), B: NewFloatValue(-1.5), Expected: -1},
+		{A: NewIntValue(1), B: NewFloatValue(1), Expected: 0},
+		{A: NewFloatValue(-3), B: NewIntValue(-3), Expected: 0},
+		{A: NewIntValue(math.MaxInt64), B: NewFloatValue(math.MaxInt64), Expected: -1},
+		{A: NewFloatValue(math.NaN()), B: NewFloatValue(math.Inf(-1)), Expected: -1},
+		{A: NewFloatValue(math.NaN()), B: NewIntValue(0), Expected: -1},
+		{A: NewFloatValue(math.NaN()), B: NewFloatValue(math.NaN()), Expected: 0},
+		{A: NewFloatValue(1e300), B: NewStringValue(""), Expected: -1},
+		{A: NewStringValue("abc"), B: NewStringValue("abd"), Expected: -1},
+		{A: NewStringValue("z"), B: NewTimeValue(t0), Expected: -1},
+		{A: NewTimeValue(t0), B: NewTimeValue(t0.Add(time.Nanosecond)), Expected: -1},
+		{A: NewTimeValue(t0), B: NewTimeValue(t0.In(time.FixedZone("X", 3600))), Expected: 0},
+		{A: NewTimeValue(t0), B: NewArrayValue(NewArray()), Expected: -1},
+		{A: mustParseJSON(t, `[1, 2]`), B: mustParseJSON(t, `[1, 2, 0]`), Expected: -1},
+		{A: mustParseJSON(t, `[1, 3]`), B: mustParseJSON(t, `[1, 2, 0]`), Expected: 1},
+		{A: mustParseJSON(t, `[{"a": 1}]`), B: mustParseJSON(t, `{}`), Expected: -1},
+		{A: mustParseJSON(t, `{"a": 1, "b": 2}`), B: mustParseJSON(t, `{"a": 1, "c": 0}`), Expected: -1},
+		{A: mustParseJSON(t, `{"a": 2}`), B: mustParseJSON(t, `{"a": 1, "b": 0}`), Expected: 1},
+		{A: mustParseJSON(t, `{"a": [1]}`), B: mustParseJSON(t, `{"a": [1]}`), Expected: 0},
+	}
+
+	for tcix, tc := range testCases {
+		stm := fmt.Sprintf("test case %d: Compare(%v, %v)", tcix, tc.A, tc.B)
+
+		if msg, ok := tcore.TAssertInt(stm, Compare(tc.A, tc.B), tc.Expected); !ok {
+			t.Error(msg)
+		}
+
+		if msg, ok := tcore.TAssertInt(stm+" reversed", Compare(tc.B, tc.A), -tc.Expected); !ok {
+			t.Error(msg)
+		}
+	}
+}
+
+func TestArray_Sort(t *testing.T) {
+	a := mustParseJSON(t, `[3, "b", null, 1.5, true, {"k": 1}, [2], "a", false, 1]`).Array()
+	a.Sort()
+	expected := mustParseJSON(t, `[null, false, true, 1, 1.5, 3, "a", "b", [2], {"k": 1}]`).Array()
+
+	if msg, ok := tcore.TAssertBool("a.Sort()", ArraysEqual(a, expected), true); !ok {
+		t.Errorf("%s - got %v", msg, a)
+	}
+}
+
+func TestArray_SortStableBy(t *testing.T) {
+	a := mustParseJSON(t, `[
+		{"name": "c", "age": 30},
+		{"name": "a", "age": 40},
+		{"name": "b"},
+		{"name": "d", "age": 30},
+		{"name": "e", "age": 20}
+	]`).Array()
+
+	if err := a.SortStableBy("/age"); err != nil {
+		t.Fatal(err)
+	}
+
+	names := ""
+
+	for _, element := range a {
+		names += element.Object()["name"].String()
+	}
+
+	if msg, ok := tcore.TAssertString("names", names, "becda"); !ok {
+		t.Error(msg)
+	}
+
+	if err := a.SortBy("name"); err == nil {
+		t.Error("an error was expected for a malformed pointer")
+	}
+
+	if err := a.SortBy("/name"); err != nil {
+		t.Fatal(err)
+	}
+
+	if msg, ok := tcore.TAssertString("a[0].name", a[0].Object()["name"].String(), "a"); !ok {
+		t.Error(msg)
+	}
+}
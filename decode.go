@@ -0,0 +1,466 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DecodeOptions controls DecodeWith
+type DecodeOptions struct {
+	// WeaklyTyped converts a Value of the wrong Type with the Coerce functions, e.g. the String "42" into an int field,
+	// instead of reporting an error
+	WeaklyTyped bool
+}
+
+// DecodeError reports a location that could not be decoded
+type DecodeError struct {
+	Path Path
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	if len(e.Path) == 0 {
+		return "(root): " + e.Err.Error()
+	}
+
+	return e.Path.String() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the reason that the location could not be decoded
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeErrors holds every DecodeError found by Decode, in the order they were found
+type DecodeErrors []*DecodeError
+
+func (e DecodeErrors) Error() string {
+	messages := make([]string, len(e))
+
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d error(s) decoding the value: %s", len(e), strings.Join(messages, "; "))
+}
+
+// Decode stores v in the Go value that out points to, walking structs, maps, slices, arrays and pointers with
+// reflection. Struct fields are named by their value tag, or else their json tag, or else the field name, which is also
+// matched without regard to case. A tag of "-" skips the field, and the fields of embedded structs are treated as
+// fields of the outer struct. Time is stored into time.Time, a String into any encoding.TextUnmarshaler, Bytes or a
+// base64 String into a []byte, and an Int into a float field. A Null sets pointers, interfaces, maps and slices to nil,
+// and leaves other targets unchanged.
+//
+// Decoding carries on past a location that does not fit, so the error, when there is one, is a DecodeErrors listing
+// every such location.
+func Decode(v Value, out interface{}) error {
+	return DecodeWith(v, out, DecodeOptions{})
+}
+
+// DecodeWith is Decode with options
+func DecodeWith(v Value, out interface{}, opts DecodeOptions) error {
+	rv := reflect.ValueOf(out)
+
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("the target of Decode must be a non-nil pointer")
+	}
+
+	d := decoder{opts: opts}
+	d.decode(Path{}, v, rv.Elem())
+
+	if len(d.errs) > 0 {
+		return d.errs
+	}
+
+	return nil
+}
+
+// Private
+
+var (
+	valueReflectType           = reflect.TypeOf(Value{})
+	timeReflectType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerReflectType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+type decoder struct {
+	opts DecodeOptions
+	errs DecodeErrors
+}
+
+func (d *decoder) fail(path Path, format string, args ...interface{}) {
+	d.errs = append(d.errs, &DecodeError{Path: path, Err: fmt.Errorf(format, args...)})
+}
+
+func (d *decoder) mismatch(path Path, v Value, rv reflect.Value) {
+	d.fail(path, "cannot decode %s into %s", v.Type().String(), rv.Type().String())
+}
+
+func (d *decoder) decode(path Path, v Value, rv reflect.Value) {
+	if rv.Type() == valueReflectType {
+		rv.Set(reflect.ValueOf(v.Clone()))
+		return
+	}
+
+	if v.IsNull() {
+		switch rv.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			rv.Set(reflect.Zero(rv.Type()))
+		}
+		return
+	}
+
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		d.decode(path, v, rv.Elem())
+		return
+	}
+
+	if rv.Type() == timeReflectType && v.IsTime() {
+		rv.Set(reflect.ValueOf(v.Time()))
+		return
+	}
+
+	if v.IsString() && rv.CanAddr() && rv.Addr().Type().Implements(textUnmarshalerReflectType) {
+		if err := rv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(v.String())); err != nil {
+			d.errs = append(d.errs, &DecodeError{Path: path, Err: err})
+		}
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			d.mismatch(path, v, rv)
+			return
+		}
+		rv.Set(reflect.ValueOf(v.Interface()))
+	case reflect.Bool:
+		if v, ok := d.coerce(path, v, rv, Bool); ok {
+			rv.SetBool(v.Bool())
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v, ok := d.coerceInteger(path, v, rv); ok {
+			i := int64(v.Int())
+			if rv.OverflowInt(i) {
+				d.fail(path, "%d overflows %s", i, rv.Type().String())
+				return
+			}
+			rv.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v, ok := d.coerceInteger(path, v, rv); ok {
+			i := v.Int()
+			if i < 0 || rv.OverflowUint(uint64(i)) {
+				d.fail(path, "%d overflows %s", i, rv.Type().String())
+				return
+			}
+			rv.SetUint(uint64(i))
+		}
+	case reflect.Float32, reflect.Float64:
+		if v.IsInt() {
+			rv.SetFloat(float64(v.Int()))
+		} else if v, ok := d.coerce(path, v, rv, Float); ok {
+			rv.SetFloat(v.Float())
+		}
+	case reflect.String:
+		if v, ok := d.coerce(path, v, rv, String); ok {
+			rv.SetString(v.String())
+		}
+	case reflect.Struct:
+		d.decodeStruct(path, v, rv)
+	case reflect.Map:
+		d.decodeMap(path, v, rv)
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 && v.Type() != ArrayType {
+			d.decodeBytes(path, v, rv)
+			return
+		} else if v.Type() != ArrayType {
+			d.mismatch(path, v, rv)
+			return
+		}
+		a := v.Array()
+		rv.Set(reflect.MakeSlice(rv.Type(), len(a), len(a)))
+		for i, element := range a {
+			d.decode(path.Index(i), element, rv.Index(i))
+		}
+	case reflect.Array:
+		if v.Type() != ArrayType {
+			d.mismatch(path, v, rv)
+			return
+		}
+		a := v.Array()
+		if len(a) > rv.Len() {
+			d.fail(path, "%d elements do not fit in %s", len(a), rv.Type().String())
+			return
+		}
+		rv.Set(reflect.Zero(rv.Type()))
+		for i, element := range a {
+			d.decode(path.Index(i), element, rv.Index(i))
+		}
+	default:
+		d.mismatch(path, v, rv)
+	}
+}
+
+// coerce returns v if it has Type t, or else its coerced form when the decoder is weakly typed
+func (d *decoder) coerce(path Path, v Value, rv reflect.Value, t Type) (Value, bool) {
+	if v.Type() == t {
+		return v, true
+	}
+
+	if d.opts.WeaklyTyped {
+		if coerced, ok := v.CoerceTo(t); ok {
+			return coerced, true
+		}
+	}
+
+	d.mismatch(path, v, rv)
+	return Value{}, false
+}
+
+// coerceInteger is coerce for integer targets, which also accept a Float that holds a whole number
+func (d *decoder) coerceInteger(path Path, v Value, rv reflect.Value) (Value, bool) {
+	if v.IsFloat() {
+		f := v.Float()
+		if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return NewIntValue(int(f)), true
+		} else if !d.opts.WeaklyTyped {
+			d.fail(path, "%v is not an integer", f)
+			return Value{}, false
+		}
+	}
+
+	return d.coerce(path, v, rv, Int)
+}
+
+// decodeBytes stores a copy of a Bytes Value into a byte slice. A String is decoded as standard base64, as
+// encoding/json does.
+func (d *decoder) decodeBytes(path Path, v Value, rv reflect.Value) {
+	b := v
+	if v.IsString() {
+		var ok bool
+		if b, ok = v.CoerceToBytes(); !ok {
+			d.fail(path, "the string is not valid base64")
+			return
+		}
+	} else if !v.IsBytes() {
+		d.mismatch(path, v, rv)
+		return
+	}
+
+	slice := reflect.MakeSlice(rv.Type(), len(b.Bytes()), len(b.Bytes()))
+	reflect.Copy(slice, reflect.ValueOf(b.Bytes()))
+	rv.Set(slice)
+}
+
+func (d *decoder) decodeStruct(path Path, v Value, rv reflect.Value) {
+	if v.Type() != ObjectType {
+		d.mismatch(path, v, rv)
+		return
+	}
+
+	o := v.Object()
+
+	for _, field := range structFields(rv.Type()) {
+		member, ok := o[field.name]
+
+		if !ok {
+			for _, key := range sortedKeys(o) {
+				if strings.EqualFold(key, field.name) {
+					member, ok = o[key], true
+					break
+				}
+			}
+		}
+
+		if !ok {
+			continue
+		}
+
+		if target, ok := fieldForDecode(rv, field.index); ok {
+			d.decode(path.Key(field.name), member, target)
+		} else {
+			d.fail(path.Key(field.name), "cannot set the field of an unexported embedded struct pointer")
+		}
+	}
+}
+
+func (d *decoder) decodeMap(path Path, v Value, rv reflect.Value) {
+	if v.Type() != ObjectType {
+		d.mismatch(path, v, rv)
+		return
+	}
+
+	keyType := rv.Type().Key()
+
+	if rv.IsNil() {
+		rv.Set(reflect.MakeMap(rv.Type()))
+	}
+
+	o := v.Object()
+
+	for _, key := range sortedKeys(o) {
+		k := reflect.New(keyType).Elem()
+
+		switch keyType.Kind() {
+		case reflect.String:
+			k.SetString(key)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i, err := strconv.ParseInt(key, 10, 64)
+			if err != nil || k.OverflowInt(i) {
+				d.fail(path.Key(key), "the key is not a valid %s", keyType.String())
+				continue
+			}
+			k.SetInt(i)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			u, err := strconv.ParseUint(key, 10, 64)
+			if err != nil || k.OverflowUint(u) {
+				d.fail(path.Key(key), "the key is not a valid %s", keyType.String())
+				continue
+			}
+			k.SetUint(u)
+		default:
+			d.fail(path, "cannot decode into a map keyed by %s", keyType.String())
+			return
+		}
+
+		element := reflect.New(rv.Type().Elem()).Elem()
+		d.decode(path.Key(key), o[key], element)
+		rv.SetMapIndex(k, element)
+	}
+}
+
+// structField is an exported field of a struct, or of a struct embedded in it, with the name it has in a Value
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	tagged    bool
+}
+
+// structFields lists the fields of a struct type following the rules of encoding/json: the value tag takes priority
+// over the json tag, the fields of untagged embedded structs are promoted, and of the fields that share a name the
+// shallowest wins, or the tagged one among equals. Ambiguous names are dropped.
+func structFields(t reflect.Type) []structField {
+	var candidates []structField
+	collectStructFields(t, nil, &candidates, map[reflect.Type]bool{})
+
+	byName := make(map[string][]structField)
+
+	for _, field := range candidates {
+		byName[field.name] = append(byName[field.name], field)
+	}
+
+	var fields []structField
+
+	for _, named := range byName {
+		sort.SliceStable(named, func(i, j int) bool {
+			if len(named[i].index) != len(named[j].index) {
+				return len(named[i].index) < len(named[j].index)
+			}
+			return named[i].tagged && !named[j].tagged
+		})
+
+		if len(named) > 1 && len(named[0].index) == len(named[1].index) && named[0].tagged == named[1].tagged {
+			continue
+		}
+
+		fields = append(fields, named[0])
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i].index, fields[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+
+	return fields
+}
+
+func collectStructFields(t reflect.Type, index []int, fields *[]structField, visited map[reflect.Type]bool) {
+	if visited[t] {
+		return
+	}
+
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("value")
+
+		if !hasTag {
+			tag, hasTag = f.Tag.Lookup("json")
+		}
+
+		if tag == "-" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		fieldIndex := append(append([]int{}, index...), i)
+		fieldType := f.Type
+
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if f.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			collectStructFields(fieldType, fieldIndex, fields, visited)
+			continue
+		}
+
+		if f.PkgPath != "" {
+			continue
+		}
+
+		field := structField{name: name, index: fieldIndex, tagged: hasTag && name != ""}
+
+		if name == "" {
+			field.name = f.Name
+		}
+
+		for _, option := range parts[1:] {
+			if option == "omitempty" {
+				field.omitEmpty = true
+			}
+		}
+
+		*fields = append(*fields, field)
+	}
+}
+
+// fieldForDecode follows an index path through embedded structs, allocating embedded pointers as it goes
+func fieldForDecode(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					return reflect.Value{}, false
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+
+	return rv, true
+}
@@ -0,0 +1,186 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/webern/tcore"
+)
+
+type decodeTestBase struct {
+	ID      int       `json:"id"`
+	Created time.Time `json:"created"`
+}
+
+type decodeTestAddress struct {
+	City string
+	Zip  *string `value:"zip_code" json:"zip"`
+}
+
+type decodeTestUser struct {
+	decodeTestBase
+	Name     string                      `json:"name"`
+	Tags     []string                    `json:"tags"`
+	Scores   map[string]float64          `json:"scores"`
+	Address  *decodeTestAddress          `json:"address"`
+	Counts   map[int]uint8               `json:"counts"`
+	Extra    interface{}                 `json:"extra"`
+	Raw      Value                       `json:"raw"`
+	IP       net.IP                      `json:"ip"`
+	Pair     [2]int                      `json:"pair"`
+	Ignored  string                      `json:"-"`
+	Nested   map[string][]decodeTestBase `json:"nested"`
+	internal string
+}
+
+func TestDecode(t *testing.T) {
+	created := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mustParseJSON(t, `{
+		"id": 7,
+		"name": "ann",
+		"tags": ["a", "b"],
+		"scores": {"math": 90, "art": 85.5},
+		"address": {"city": "Oslo", "zip_code": "0150"},
+		"counts": {"1": 10, "2": 20},
+		"extra": {"k": [1, "x", null]},
+		"raw": [true],
+		"ip": "10.0.0.1",
+		"pair": [3, 4],
+		"Ignored": "nope",
+		"nested": {"n": [{"id": 1}]},
+		"internal": "nope"
+	}`)
+
+	if err := v.Set("/created", NewTimeValue(created)); err != nil {
+		t.Fatal(err)
+	}
+
+	var user decodeTestUser
+
+	if err := Decode(v, &user); err != nil {
+		t.Fatal(err)
+	}
+
+	zip := "0150"
+	expected := decodeTestUser{
+		decodeTestBase: decodeTestBase{ID: 7, Created: created},
+		Name:           "ann",
+		Tags:           []string{"a", "b"},
+		Scores:         map[string]float64{"math": 90, "art": 85.5},
+		Address:        &decodeTestAddress{City: "Oslo", Zip: &zip},
+		Counts:         map[int]uint8{1: 10, 2: 20},
+		Extra:          map[string]interface{}{"k": []interface{}{1, "x", nil}},
+		Raw:            user.Raw,
+		IP:             net.ParseIP("10.0.0.1"),
+		Pair:           [2]int{3, 4},
+		Nested:         map[string][]decodeTestBase{"n": {{ID: 1}}},
+	}
+
+	if !reflect.DeepEqual(user, expected) {
+		t.Errorf("Decode(v, &user)\n got: %#v\nwant: %#v", user, expected)
+	}
+
+	if msg, ok := tcore.TAssertBool("user.Raw", user.Raw.Equals(mustParseJSON(t, `[true]`)), true); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	v := mustParseJSON(t, `{"id": "7", "name": 5, "tags": ["a", 2], "pair": [1, 2, 3], "counts": {"x": 1, "2": 300}}`)
+	var user decodeTestUser
+	err := Decode(v, &user)
+	errs, ok := err.(DecodeErrors)
+
+	if !ok {
+		t.Fatalf("expected DecodeErrors but got '%v'", err)
+	}
+
+	var paths []string
+
+	for _, e := range errs {
+		paths = append(paths, e.Path.String())
+	}
+
+	expected := "/id,/name,/tags/1,/counts/2,/counts/x,/pair"
+
+	if msg, ok := tcore.TAssertString("error paths", strings.Join(paths, ","), expected); !ok {
+		t.Error(msg + " - " + err.Error())
+	}
+
+	if msg, ok := tcore.TAssertString("user.Tags[0]", user.Tags[0], "a"); !ok {
+		t.Error(msg)
+	}
+
+	if err = Decode(v, user); err == nil {
+		t.Error("an error was expected for a non-pointer target")
+	}
+}
+
+func TestDecodeWith_WeaklyTyped(t *testing.T) {
+	var out struct {
+		I int
+		F float64
+		B bool
+		S string
+		U uint
+	}
+
+	v := mustParseJSON(t, `{"i": "42", "f": "2.5", "b": "yes", "s": 12, "u": 3.7}`)
+
+	if err := DecodeWith(v, &out, DecodeOptions{WeaklyTyped: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.I != 42 || out.F != 2.5 || !out.B || out.S != "12" || out.U != 4 {
+		t.Errorf("unexpected result %+v", out)
+	}
+
+	if err := Decode(v, &out); err == nil {
+		t.Error("an error was expected without weak typing")
+	}
+
+	var b bool
+	if err := DecodeWith(NewIntValue(1), &b, DecodeOptions{WeaklyTyped: true}); err != nil || !b {
+		t.Errorf("expected true but got %v, %v", b, err)
+	}
+
+	var i int
+	if err := DecodeWith(NewBoolValue(true), &i, DecodeOptions{WeaklyTyped: true}); err != nil || i != 1 {
+		t.Errorf("expected 1 but got %v, %v", i, err)
+	}
+}
+
+func TestDecode_Bytes(t *testing.T) {
+	var out struct {
+		Raw    []byte
+		Text   []byte
+		Digest string
+	}
+
+	v := NewObjectValue(Object{
+		"raw":    NewBytesValue([]byte{0xff, 0}),
+		"text":   NewStringValue("aGk="),
+		"digest": NewBytesValue([]byte("hi")),
+	})
+
+	if err := DecodeWith(v, &out, DecodeOptions{WeaklyTyped: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(out.Raw, []byte{0xff, 0}) || string(out.Text) != "hi" || out.Digest != "aGk=" {
+		t.Errorf("unexpected result %+v", out)
+	}
+
+	if err := Decode(NewStringValue("not base64!"), &out.Raw); err == nil {
+		t.Error("an error was expected for a string that is not base64")
+	}
+
+	if err := Decode(v, &out); err == nil {
+		t.Error("an error was expected for Bytes into a string without weak typing")
+	}
+}
@@ -0,0 +1,218 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ChangeKind says how a location differs between the two sides of a Diff
+type ChangeKind int
+
+const (
+	Added        ChangeKind = iota // Added means the location exists only on the right
+	Removed                        // Removed means the location exists only on the left
+	TypeChanged                    // TypeChanged means the location holds a different Type on each side
+	ValueChanged                   // ValueChanged means the location holds the same Type but a different value
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case TypeChanged:
+		return "type changed"
+	case ValueChanged:
+		return "value changed"
+	}
+
+	return "unknown"
+}
+
+// Change is a single difference found by Diff. From is Null for an Added change and To is Null for a Removed one.
+type Change struct {
+	Kind ChangeKind
+	Path Path
+	From Value
+	To   Value
+}
+
+// String renders the Change as a hunk in the style of a unified diff, with removed lines prefixed by - and added lines
+// by +
+func (c Change) String() string {
+	b := strings.Builder{}
+	b.WriteString("@@ ")
+
+	if len(c.Path) == 0 {
+		b.WriteString("(root)")
+	} else {
+		b.WriteString(c.Path.String())
+	}
+
+	b.WriteString(" @@ ")
+	b.WriteString(c.Kind.String())
+
+	if c.Kind == TypeChanged {
+		b.WriteString(" from ")
+		b.WriteString(c.From.Type().String())
+		b.WriteString(" to ")
+		b.WriteString(c.To.Type().String())
+	}
+
+	b.WriteString("\n")
+
+	if c.Kind != Added {
+		writeDiffLines(&b, "- ", c.From)
+	}
+
+	if c.Kind != Removed {
+		writeDiffLines(&b, "+ ", c.To)
+	}
+
+	return b.String()
+}
+
+// Changes is the list of differences returned by Diff
+type Changes []Change
+
+// String renders every Change in order, see Change.String
+func (c Changes) String() string {
+	b := strings.Builder{}
+
+	for _, change := range c {
+		b.WriteString(change.String())
+	}
+
+	return b.String()
+}
+
+// DiffOptions controls how Diff compares Values. Object is a map with no key order, so key order never produces a
+// change and needs no option.
+type DiffOptions struct {
+	// ArraysAsSets compares Arrays as multisets, ignoring element order. Elements are matched with Equals, and each
+	// unmatched element is reported as Added or Removed at its own index.
+	ArraysAsSets bool
+}
+
+// Diff returns the differences between a and b, walking Objects key by key and Arrays index by index. Leaves are
+// compared with Equals. Object keys are visited in sorted order so that the result is deterministic.
+func Diff(a, b Value) Changes {
+	return DiffWith(a, b, DiffOptions{})
+}
+
+// DiffWith is Diff with options
+func DiffWith(a, b Value, opts DiffOptions) Changes {
+	return appendDiff(Changes{}, a, b, Path{}, opts)
+}
+
+// Private
+
+func appendDiff(changes Changes, a, b Value, path Path, opts DiffOptions) Changes {
+	if a.Type() != b.Type() {
+		return append(changes, Change{Kind: TypeChanged, Path: path, From: a, To: b})
+	}
+
+	switch a.Type() {
+	case ObjectType:
+		return appendObjectDiff(changes, a.Object(), b.Object(), path, opts)
+	case ArrayType:
+		if opts.ArraysAsSets {
+			return appendSetDiff(changes, a.Array(), b.Array(), path)
+		}
+		return appendArrayDiff(changes, a.Array(), b.Array(), path, opts)
+	}
+
+	if !a.Equals(b) {
+		changes = append(changes, Change{Kind: ValueChanged, Path: path, From: a, To: b})
+	}
+
+	return changes
+}
+
+func appendObjectDiff(changes Changes, a, b Object, path Path, opts DiffOptions) Changes {
+	keys := NewObject(len(a) + len(b))
+
+	for key := range a {
+		keys[key] = Value{}
+	}
+
+	for key := range b {
+		keys[key] = Value{}
+	}
+
+	for _, key := range sortedKeys(keys) {
+		aMember, inA := a[key]
+		bMember, inB := b[key]
+
+		switch {
+		case !inB:
+			changes = append(changes, Change{Kind: Removed, Path: path.Key(key), From: aMember})
+		case !inA:
+			changes = append(changes, Change{Kind: Added, Path: path.Key(key), To: bMember})
+		default:
+			changes = appendDiff(changes, aMember, bMember, path.Key(key), opts)
+		}
+	}
+
+	return changes
+}
+
+func appendArrayDiff(changes Changes, a, b Array, path Path, opts DiffOptions) Changes {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		switch {
+		case i >= len(b):
+			changes = append(changes, Change{Kind: Removed, Path: path.Index(i), From: a[i]})
+		case i >= len(a):
+			changes = append(changes, Change{Kind: Added, Path: path.Index(i), To: b[i]})
+		default:
+			changes = appendDiff(changes, a[i], b[i], path.Index(i), opts)
+		}
+	}
+
+	return changes
+}
+
+func appendSetDiff(changes Changes, a, b Array, path Path) Changes {
+	matched := make([]bool, len(b))
+
+	for i := range a {
+		found := false
+
+		for j := range b {
+			if !matched[j] && a[i].Equals(b[j]) {
+				matched[j] = true
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			changes = append(changes, Change{Kind: Removed, Path: path.Index(i), From: a[i]})
+		}
+	}
+
+	for j := range b {
+		if !matched[j] {
+			changes = append(changes, Change{Kind: Added, Path: path.Index(j), To: b[j]})
+		}
+	}
+
+	return changes
+}
+
+func writeDiffLines(b *strings.Builder, prefix string, v Value) {
+	text, err := json.MarshalIndent(v, "", "  ")
+
+	if err != nil {
+		text = []byte(v.Type().String())
+	}
+
+	for _, line := range strings.Split(string(text), "\n") {
+		b.WriteString(prefix)
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+}
@@ -0,0 +1,95 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/webern/tcore"
+)
+
+func TestDiff(t *testing.T) {
+	type expectedChange struct {
+		Kind ChangeKind
+		Path string
+	}
+
+	testCases := []struct {
+		A            string
+		B            string
+		ArraysAsSets bool
+		Expected     []expectedChange
+	}{
+		{A: `{"a": 1}`, B: `{"a": 1}`},
+		{
+			A:        `{"a": 1, "b": 2, "c": {"d": true}}`,
+			B:        `{"a": 1, "b": "2", "c": {"d": false}, "e": null}`,
+			Expected: []expectedChange{{TypeChanged, "/b"}, {ValueChanged, "/c/d"}, {Added, "/e"}},
+		},
+		{
+			A:        `{"x": [1, 2, 3]}`,
+			B:        `{"x": [1, 5]}`,
+			Expected: []expectedChange{{ValueChanged, "/x/1"}, {Removed, "/x/2"}},
+		},
+		{
+			A:        `[1, 2]`,
+			B:        `[1, 2, {"a": 1}]`,
+			Expected: []expectedChange{{Added, "/2"}},
+		},
+		{
+			A:            `[1, 2, 3, 2]`,
+			B:            `[3, 2, 1]`,
+			ArraysAsSets: true,
+			Expected:     []expectedChange{{Removed, "/3"}},
+		},
+		{
+			A:            `[1, 2]`,
+			B:            `[2, 4]`,
+			ArraysAsSets: true,
+			Expected:     []expectedChange{{Removed, "/0"}, {Added, "/1"}},
+		},
+		{
+			A:        `"a"`,
+			B:        `["a"]`,
+			Expected: []expectedChange{{TypeChanged, ""}},
+		},
+	}
+
+	for tcix, tc := range testCases {
+		stm := fmt.Sprintf("test case %d: DiffWith(%s, %s)", tcix, tc.A, tc.B)
+		changes := DiffWith(mustParseJSON(t, tc.A), mustParseJSON(t, tc.B), DiffOptions{ArraysAsSets: tc.ArraysAsSets})
+
+		if msg, ok := tcore.TAssertInt(stm+" count", len(changes), len(tc.Expected)); !ok {
+			t.Error(msg + "\n" + changes.String())
+			continue
+		}
+
+		for i, expected := range tc.Expected {
+			if msg, ok := tcore.TAssertInt(stm+" kind", int(changes[i].Kind), int(expected.Kind)); !ok {
+				t.Error(msg)
+			}
+			if msg, ok := tcore.TAssertString(stm+" path", changes[i].Path.String(), expected.Path); !ok {
+				t.Error(msg)
+			}
+		}
+	}
+}
+
+func TestChanges_String(t *testing.T) {
+	changes := Diff(mustParseJSON(t, `{"a": 1, "b": "x"}`), mustParseJSON(t, `{"a": 2, "c": [true]}`))
+	expected := "@@ /a @@ value changed\n- 1\n+ 2\n" +
+		"@@ /b @@ removed\n- \"x\"\n" +
+		"@@ /c @@ added\n+ [\n+   true\n+ ]\n"
+
+	if msg, ok := tcore.TAssertString("changes.String()", changes.String(), expected); !ok {
+		t.Error(msg)
+	}
+
+	root := Diff(NewIntValue(1), NewStringValue("1"))
+	expected = "@@ (root) @@ type changed from VALUE_INTEGER to VALUE_STRING\n- 1\n+ \"1\"\n"
+
+	if msg, ok := tcore.TAssertString("root.String()", root.String(), expected); !ok {
+		t.Error(msg)
+	}
+}
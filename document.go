@@ -0,0 +1,484 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Document is a JSON, JSONC or JSON5 text that can be edited in terms of Values without disturbing the parts of the
+// text that were not edited. Comments, whitespace, key order and the spelling of untouched literals are all preserved
+// byte for byte. New content is serialized as JSON and indented to match its siblings.
+type Document struct {
+	src  []byte
+	root *docNode
+}
+
+// ParseDocument parses JSON, JSONC or JSON5 text into a Document
+func ParseDocument(data []byte) (*Document, error) {
+	src := make([]byte, len(data))
+	copy(src, data)
+	root, err := parseDocTree(src)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Document{src: src, root: root}, nil
+}
+
+// Bytes returns the current text of the document
+func (d *Document) Bytes() []byte {
+	b := make([]byte, len(d.src))
+	copy(b, d.src)
+	return b
+}
+
+// Value returns the whole document as a Value
+func (d *Document) Value() Value {
+	v, _ := d.Get(nil)
+	return v
+}
+
+// Get returns the Value found at path
+func (d *Document) Get(path Path) (Value, error) {
+	node, err := d.find(path)
+
+	if err != nil {
+		return Value{}, err
+	}
+
+	return ParseJSON5(d.src[node.start:node.end])
+}
+
+// Set replaces the value at path with v. If path names a missing property of an object, the property is added after
+// the last existing property. If path names the index just past the end of an array, v is appended.
+func (d *Document) Set(path Path, v Value) error {
+	if len(path) == 0 {
+		text, err := d.render(v, d.root.start)
+		if err != nil {
+			return err
+		}
+		return d.splice(d.root.start, d.root.end, text)
+	}
+
+	parent, err := d.find(path[:len(path)-1])
+
+	if err != nil {
+		return err
+	}
+
+	last := path[len(path)-1]
+
+	switch parent.kind {
+	case ObjectType:
+		if entry, ok := parent.member(last); ok {
+			return d.replace(entry.node, v)
+		}
+		return d.insertEntry(parent, len(parent.entries), last, v)
+	case ArrayType:
+		if i, ok := arrayIndex(last, len(parent.entries)); ok {
+			return d.replace(parent.entries[i].node, v)
+		} else if i, ok := arrayIndex(last, len(parent.entries)+1); ok {
+			return d.insertEntry(parent, i, "", v)
+		}
+	}
+
+	return fmt.Errorf("the document has no value at %s", path.String())
+}
+
+// Insert inserts v into an array. The last segment of path is the index that v will have once it is inserted, and may
+// be equal to the length of the array in order to append.
+func (d *Document) Insert(path Path, v Value) error {
+	if len(path) == 0 {
+		return fmt.Errorf("the document root is not an array element")
+	}
+
+	parent, err := d.find(path[:len(path)-1])
+
+	if err != nil {
+		return err
+	}
+
+	if parent.kind != ArrayType {
+		return fmt.Errorf("the document value at %s is not an array", path[:len(path)-1].String())
+	}
+
+	i, ok := arrayIndex(path[len(path)-1], len(parent.entries)+1)
+
+	if !ok {
+		return fmt.Errorf("the array index at %s is out of range", path.String())
+	}
+
+	return d.insertEntry(parent, i, "", v)
+}
+
+// Delete removes an object property or array element, together with its separating comma and any comments on the
+// lines directly above it
+func (d *Document) Delete(path Path) error {
+	if len(path) == 0 {
+		return fmt.Errorf("the document root cannot be deleted")
+	}
+
+	parent, err := d.find(path[:len(path)-1])
+
+	if err != nil {
+		return err
+	}
+
+	index := -1
+	last := path[len(path)-1]
+
+	switch parent.kind {
+	case ObjectType:
+		for i, entry := range parent.entries {
+			if entry.key == last {
+				index = i
+			}
+		}
+	case ArrayType:
+		if i, ok := arrayIndex(last, len(parent.entries)); ok {
+			index = i
+		}
+	}
+
+	if index < 0 {
+		return fmt.Errorf("the document has no value at %s", path.String())
+	}
+
+	entries := parent.entries
+
+	if len(entries) == 1 {
+		return d.splice(parent.start+1, parent.close, "")
+	} else if index < len(entries)-1 {
+		return d.splice(d.leadingStart(parent, index), d.leadingStart(parent, index+1), "")
+	}
+
+	// the last entry keeps the previous entry's comma if it has a trailing comma of its own
+	end := entries[index].node.end
+	s := json5Scanner{data: d.src, pos: end}
+
+	if tok, err := s.next(); err == nil && tok.kind == json5Comma {
+		return d.splice(d.leadingStart(parent, index), d.lineCommentEnd(tok.end), "")
+	}
+
+	// otherwise only the previous entry's comma goes, and anything after it, such as a comment, stays
+	s = json5Scanner{data: d.src, pos: entries[index-1].node.end}
+	comma, err := s.next()
+
+	if err != nil || comma.kind != json5Comma {
+		return fmt.Errorf("the document has no comma before %s", path.String())
+	}
+
+	kept := string(d.src[comma.end:d.leadingStart(parent, index)])
+
+	if strings.TrimSpace(kept) == "" {
+		kept = ""
+	}
+
+	return d.splice(comma.start, d.lineCommentEnd(end), kept)
+}
+
+// Private
+
+// docNode is a value in a Document, along with the byte offsets where its text begins and ends
+type docNode struct {
+	kind    Type
+	start   int
+	end     int
+	close   int // offset of the closing brace or bracket of an object or array
+	entries []docEntry
+}
+
+// docEntry is an object property or array element. For array elements start is the start of the value, for object
+// properties it is the start of the key.
+type docEntry struct {
+	key    string
+	start  int
+	keyEnd int
+	node   *docNode
+}
+
+func (n *docNode) member(key string) (docEntry, bool) {
+	found := false
+	var member docEntry
+
+	// when a key is duplicated the last one wins, just as it does when parsing
+	for _, entry := range n.entries {
+		if entry.key == key {
+			member = entry
+			found = true
+		}
+	}
+
+	return member, found
+}
+
+func (d *Document) find(path Path) (*docNode, error) {
+	node := d.root
+
+	for depth, segment := range path {
+		var entry docEntry
+		ok := false
+
+		if node.kind == ObjectType {
+			entry, ok = node.member(segment)
+		} else if node.kind == ArrayType {
+			var i int
+			if i, ok = arrayIndex(segment, len(node.entries)); ok {
+				entry = node.entries[i]
+			}
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("the document has no value at %s", path[:depth+1].String())
+		}
+
+		node = entry.node
+	}
+
+	return node, nil
+}
+
+// leadingStart returns the offset at which the text belonging to entry number index begins. When the entry is on a
+// line of its own that is the end of the previous line, so that comments above an entry are deleted along with it.
+func (d *Document) leadingStart(parent *docNode, index int) int {
+	prevEnd := parent.start + 1
+
+	if index > 0 {
+		prevEnd = parent.entries[index-1].node.end
+	}
+
+	start := parent.entries[index].start
+
+	if newline := strings.Index(string(d.src[prevEnd:start]), "\n"); newline >= 0 {
+		return prevEnd + newline
+	}
+
+	return start
+}
+
+// lineCommentEnd returns the end of a // comment that follows offset on the same line, or offset if there is none
+func (d *Document) lineCommentEnd(offset int) int {
+	rest := string(d.src[offset:])
+	trimmed := strings.TrimLeft(rest, " \t")
+
+	if !strings.HasPrefix(trimmed, "//") {
+		return offset
+	}
+
+	if newline := strings.Index(trimmed, "\n"); newline >= 0 {
+		return offset + len(rest) - len(trimmed) + newline
+	}
+
+	return len(d.src)
+}
+
+func (d *Document) replace(node *docNode, v Value) error {
+	text, err := d.render(v, node.start)
+
+	if err != nil {
+		return err
+	}
+
+	return d.splice(node.start, node.end, text)
+}
+
+// insertEntry adds a new property (for objects) or element (for arrays) so that it becomes entry number index
+func (d *Document) insertEntry(parent *docNode, index int, key string, v Value) error {
+	multiline := strings.Contains(string(d.src[parent.start:parent.close]), "\n")
+	entries := parent.entries
+	indent := ""
+
+	if len(entries) > 0 {
+		indent = d.lineIndent(entries[0].start)
+	} else if multiline {
+		indent = d.lineIndent(parent.start) + d.indentUnit()
+	}
+
+	var text string
+	var err error
+
+	if multiline {
+		text, err = d.renderIndented(v, indent)
+	} else {
+		text, err = d.render(v, -1)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if parent.kind == ObjectType {
+		keyText, _ := json.Marshal(key)
+		colon := ": "
+		if len(entries) > 0 {
+			if between := string(d.src[entries[0].keyEnd:entries[0].node.start]); strings.TrimSpace(between) == ":" {
+				colon = between
+			}
+		}
+		text = string(keyText) + colon + text
+	}
+
+	separator := " "
+
+	if multiline {
+		separator = "\n" + indent
+	} else if len(entries) == 0 {
+		separator = ""
+	}
+
+	switch {
+	case len(entries) == 0:
+		return d.splice(parent.start+1, parent.start+1, separator+text)
+	case index < len(entries):
+		return d.splice(entries[index].start, entries[index].start, text+","+separator)
+	}
+
+	end := entries[len(entries)-1].node.end
+	return d.splice(end, end, ","+separator+text)
+}
+
+// render serializes v as JSON, indenting it to match the line at offset. A negative offset gives compact JSON.
+func (d *Document) render(v Value, offset int) (string, error) {
+	if offset >= 0 && strings.Contains(string(d.src), "\n") {
+		return d.renderIndented(v, d.lineIndent(offset))
+	}
+
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+func (d *Document) renderIndented(v Value, indent string) (string, error) {
+	b, err := json.MarshalIndent(v, indent, d.indentUnit())
+	return string(b), err
+}
+
+// lineIndent returns the whitespace at the start of the line containing offset
+func (d *Document) lineIndent(offset int) string {
+	lineStart := strings.LastIndex(string(d.src[:offset]), "\n") + 1
+	end := lineStart
+
+	for end < len(d.src) && (d.src[end] == ' ' || d.src[end] == '\t') {
+		end++
+	}
+
+	return string(d.src[lineStart:end])
+}
+
+// indentUnit guesses the indentation used by the document from the first indented line, defaulting to two spaces
+func (d *Document) indentUnit() string {
+	for _, line := range strings.Split(string(d.src), "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if len(trimmed) > 0 && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+
+	return "  "
+}
+
+// splice replaces the bytes from start to end with text and re-parses the document
+func (d *Document) splice(start, end int, text string) error {
+	src := make([]byte, 0, len(d.src)-(end-start)+len(text))
+	src = append(src, d.src[:start]...)
+	src = append(src, text...)
+	src = append(src, d.src[end:]...)
+	root, err := parseDocTree(src)
+
+	if err != nil {
+		return err
+	}
+
+	d.src = src
+	d.root = root
+	return nil
+}
+
+func parseDocTree(src []byte) (*docNode, error) {
+	p := json5Parser{scanner: json5Scanner{data: src}}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	root, err := parseDocNode(&p)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind != json5EOF {
+		return nil, p.unexpected()
+	}
+
+	return root, nil
+}
+
+func parseDocNode(p *json5Parser) (*docNode, error) {
+	tok := p.tok
+
+	if tok.kind != json5LeftBrace && tok.kind != json5LeftBracket {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return &docNode{kind: v.Type(), start: tok.start, end: tok.end}, nil
+	}
+
+	node := &docNode{kind: ObjectType, start: tok.start}
+	closing := json5RightBrace
+
+	if tok.kind == json5LeftBracket {
+		node.kind = ArrayType
+		closing = json5RightBracket
+	}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	for p.tok.kind != closing {
+		entry := docEntry{start: p.tok.start}
+
+		if node.kind == ObjectType {
+			if p.tok.kind != json5String && p.tok.kind != json5Ident {
+				return nil, p.unexpected()
+			}
+			entry.key = p.tok.text
+			entry.keyEnd = p.tok.end
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != json5Colon {
+				return nil, p.unexpected()
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+
+		child, err := parseDocNode(p)
+
+		if err != nil {
+			return nil, err
+		}
+
+		entry.node = child
+		node.entries = append(node.entries, entry)
+
+		if p.tok.kind == json5Comma {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		} else if p.tok.kind != closing {
+			return nil, p.unexpected()
+		}
+	}
+
+	node.close = p.tok.start
+	node.end = p.tok.end
+	return node, p.advance()
+}
@@ -0,0 +1,205 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/webern/tcore"
+)
+
+const testDocument = `// service configuration
+{
+  "name": "api", // the service name
+  /* ports we listen on */
+  "ports": [80, 443],
+  "debug": false,
+}
+`
+
+func TestDocument_Edits(t *testing.T) {
+	type TestCase struct {
+		Source   string // testDocument when empty
+		Edit     func(d *Document) error
+		Expected string
+	}
+
+	testCases := []TestCase{
+		{
+			Edit: func(d *Document) error { return d.Set(Path{"name"}, NewStringValue("web")) },
+			Expected: `// service configuration
+{
+  "name": "web", // the service name
+  /* ports we listen on */
+  "ports": [80, 443],
+  "debug": false,
+}
+`,
+		},
+		{
+			Edit: func(d *Document) error { return d.Set(Path{"replicas"}, NewIntValue(3)) },
+			Expected: `// service configuration
+{
+  "name": "api", // the service name
+  /* ports we listen on */
+  "ports": [80, 443],
+  "debug": false,
+  "replicas": 3,
+}
+`,
+		},
+		{
+			Edit: func(d *Document) error { return d.Insert(Path{"ports", "1"}, NewIntValue(8080)) },
+			Expected: `// service configuration
+{
+  "name": "api", // the service name
+  /* ports we listen on */
+  "ports": [80, 8080, 443],
+  "debug": false,
+}
+`,
+		},
+		{
+			Edit: func(d *Document) error { return d.Set(Path{"ports", "2"}, NewIntValue(8443)) },
+			Expected: `// service configuration
+{
+  "name": "api", // the service name
+  /* ports we listen on */
+  "ports": [80, 443, 8443],
+  "debug": false,
+}
+`,
+		},
+		{
+			Edit: func(d *Document) error { return d.Delete(Path{"ports"}) },
+			Expected: `// service configuration
+{
+  "name": "api", // the service name
+  "debug": false,
+}
+`,
+		},
+		{
+			Edit: func(d *Document) error { return d.Delete(Path{"debug"}) },
+			Expected: `// service configuration
+{
+  "name": "api", // the service name
+  /* ports we listen on */
+  "ports": [80, 443],
+}
+`,
+		},
+		{
+			Edit: func(d *Document) error { return d.Set(Path{"limits"}, NewObjectValue(Object{"cpu": NewIntValue(2)})) },
+			Expected: `// service configuration
+{
+  "name": "api", // the service name
+  /* ports we listen on */
+  "ports": [80, 443],
+  "debug": false,
+  "limits": {
+    "cpu": 2
+  },
+}
+`,
+		},
+		{
+			Source:   "{\n  \"a\": 1, // keep me\n  \"b\": 2\n}\n",
+			Edit:     func(d *Document) error { return d.Delete(Path{"b"}) },
+			Expected: "{\n  \"a\": 1 // keep me\n}\n",
+		},
+		{
+			Source:   "{\n  \"a\": 1, // keep me\n  \"b\": 2, // drop me\n}\n",
+			Edit:     func(d *Document) error { return d.Delete(Path{"b"}) },
+			Expected: "{\n  \"a\": 1, // keep me\n}\n",
+		},
+		{
+			Source:   "[1, 2 /* two */, 3 // drop me\n]",
+			Edit:     func(d *Document) error { return d.Delete(Path{"2"}) },
+			Expected: "[1, 2 /* two */\n]",
+		},
+		{
+			Source:   `{"a": 1, "b": 2}`,
+			Edit:     func(d *Document) error { return d.Delete(Path{"b"}) },
+			Expected: `{"a": 1}`,
+		},
+	}
+
+	for tcix, tc := range testCases {
+		d, err := ParseDocument([]byte(testDocument))
+
+		if tc.Source != "" {
+			d, err = ParseDocument([]byte(tc.Source))
+		}
+
+		if msg, ok := tcore.TErr("ParseDocument", err); !ok {
+			t.Fatal(msg)
+		}
+
+		stm := fmt.Sprintf("test case %d: tc.Edit(d)", tcix)
+		err = tc.Edit(d)
+
+		if msg, ok := tcore.TErr(stm, err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		stm = fmt.Sprintf("test case %d: string(d.Bytes())", tcix)
+		if msg, ok := tcore.TAssertString(stm, string(d.Bytes()), tc.Expected); !ok {
+			t.Error(msg)
+		}
+	}
+}
+
+func TestDocument_Value(t *testing.T) {
+	d, err := ParseDocument([]byte(testDocument))
+
+	if msg, ok := tcore.TErr("ParseDocument", err); !ok {
+		t.Fatal(msg)
+	}
+
+	expected := NewObjectValue(Object{
+		"name":  NewStringValue("api"),
+		"ports": NewArrayValue(Array{NewIntValue(80), NewIntValue(443)}),
+		"debug": NewBoolValue(false),
+	})
+
+	actual := d.Value()
+
+	if msg, ok := tcore.TAssertBool("d.Value().Equals(expected)", actual.Equals(expected), true); !ok {
+		t.Error(msg)
+	}
+
+	if err = d.Set(Path{"missing", "x"}, NewIntValue(1)); err == nil {
+		t.Error("an error was expected when setting below a missing property")
+	}
+
+	if err = d.Insert(Path{"name", "0"}, NewIntValue(1)); err == nil {
+		t.Error("an error was expected when inserting into a string")
+	}
+}
+
+func TestDocument_EmptyContainers(t *testing.T) {
+	d, err := ParseDocument([]byte(`{"a": [], "b": {}}`))
+
+	if msg, ok := tcore.TErr("ParseDocument", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if err = d.Insert(Path{"a", "0"}, NewStringValue("x")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = d.Set(Path{"b", "c"}, NewBoolValue(true)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = d.Delete(Path{"a", "0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if msg, ok := tcore.TAssertString("string(d.Bytes())", string(d.Bytes()), `{"a": [], "b": {"c": true}}`); !ok {
+		t.Error(msg)
+	}
+}
@@ -0,0 +1,193 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// GetPath returns the Value at a dotted path expression such as a.b[2].c. Keys are separated by dots and array indices
+// are written in brackets. A key that contains dots or brackets can be quoted as a JSON string in brackets, e.g.
+// a["b.c"]. The empty expression refers to v itself. Errors are PathErrors.
+func (v Value) GetPath(expr string) (Value, error) {
+	segments, err := parseDotPath(expr)
+
+	if err != nil {
+		return Value{}, err
+	}
+
+	current := v
+
+	for i, s := range segments {
+		switch {
+		case s.isIndex && current.Type() == ArrayType:
+			if s.index >= len(current.arr) {
+				return Value{}, &PathError{Path: expr, Segment: s.text, Index: i, Err: ErrPathIndex}
+			}
+			current = current.arr[s.index]
+		case !s.isIndex && current.Type() == ObjectType:
+			c, ok := current.obj[s.text]
+			if !ok {
+				return Value{}, &PathError{Path: expr, Segment: s.text, Index: i, Err: ErrPathNotFound}
+			}
+			current = c
+		default:
+			return Value{}, &PathError{Path: expr, Segment: s.text, Index: i, Err: ErrPathTypeMismatch}
+		}
+	}
+
+	return current, nil
+}
+
+// SetPath stores x at a dotted path expression, see GetPath for the syntax. Missing containers are created as needed,
+// an Object for a key and an Array for an index, and Arrays are extended with Nulls to reach an index. An index more
+// than MaxSetPathPadding past the end of an Array is an ErrPathIndex. Null values on the way are replaced by new
+// containers, but any other value of the wrong Type is an ErrPathTypeMismatch. On error v is left unchanged. The
+// change is made in place and is visible through v.
+func (v *Value) SetPath(expr string, x Value) error {
+	segments, err := parseDotPath(expr)
+
+	if err != nil {
+		return err
+	}
+
+	return setDotPath(v, expr, segments, 0, x)
+}
+
+// MaxSetPathPadding is the most Nulls that SetPath adds to an Array to reach an index, so that a short expression such
+// as "a[1000000000]" cannot allocate an enormous Array
+const MaxSetPathPadding = 1024
+
+// Private
+
+type dotSegment struct {
+	text    string // the key, or the index in decimal
+	index   int
+	isIndex bool
+}
+
+func parseDotPath(expr string) ([]dotSegment, error) {
+	var segments []dotSegment
+	pos := 0
+
+	for pos < len(expr) {
+		start := pos
+		syntaxError := &PathError{Path: expr, Segment: expr[start:], Index: len(segments), Err: ErrPathSyntax}
+
+		switch {
+		case expr[pos] == '[' && pos+1 < len(expr) && expr[pos+1] == '"':
+			end := pos + 2
+			for end < len(expr) && expr[end] != '"' {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			var key string
+			if end+1 >= len(expr) || expr[end+1] != ']' || json.Unmarshal([]byte(expr[pos+1:end+1]), &key) != nil {
+				return nil, syntaxError
+			}
+			segments = append(segments, dotSegment{text: key})
+			pos = end + 2
+		case expr[pos] == '[':
+			end := pos + 1
+			for end < len(expr) && expr[end] >= '0' && expr[end] <= '9' {
+				end++
+			}
+			if end == pos+1 || end >= len(expr) || expr[end] != ']' {
+				return nil, syntaxError
+			}
+			index, err := strconv.Atoi(expr[pos+1 : end])
+			if err != nil {
+				return nil, syntaxError
+			}
+			segments = append(segments, dotSegment{text: expr[pos+1 : end], index: index, isIndex: true})
+			pos = end + 1
+		case (pos == 0 && expr[pos] != '.') || (pos > 0 && expr[pos] == '.'):
+			if pos > 0 {
+				pos++
+			}
+			end := pos
+			for end < len(expr) && expr[end] != '.' && expr[end] != '[' {
+				end++
+			}
+			if end == pos {
+				return nil, syntaxError
+			}
+			segments = append(segments, dotSegment{text: expr[pos:end]})
+			pos = end
+		default:
+			return nil, syntaxError
+		}
+	}
+
+	return segments, nil
+}
+
+// setDotPath stores x below v, changing v only once everything below it has succeeded
+func setDotPath(v *Value, expr string, segments []dotSegment, depth int, x Value) error {
+	if depth == len(segments) {
+		*v = x
+		return nil
+	}
+
+	s := segments[depth]
+	mismatch := &PathError{Path: expr, Segment: s.text, Index: depth, Err: ErrPathTypeMismatch}
+
+	if s.isIndex {
+		var a Array
+
+		switch v.Type() {
+		case Null:
+			a = NewArray()
+		case ArrayType:
+			a = v.arr
+		default:
+			return mismatch
+		}
+
+		if s.index-len(a) > MaxSetPathPadding {
+			return &PathError{Path: expr, Segment: s.text, Index: depth, Err: ErrPathIndex}
+		}
+
+		var c Value
+
+		if s.index < len(a) {
+			c = a[s.index]
+		}
+
+		if err := setDotPath(&c, expr, segments, depth+1, x); err != nil {
+			return err
+		}
+
+		for len(a) <= s.index {
+			a = append(a, Value{})
+		}
+
+		a[s.index] = c
+		v.SetArray(a)
+		return nil
+	}
+
+	var o Object
+
+	switch v.Type() {
+	case Null:
+		o = NewObject(0)
+	case ObjectType:
+		o = v.obj
+	default:
+		return mismatch
+	}
+
+	c := o[s.text]
+
+	if err := setDotPath(&c, expr, segments, depth+1, x); err != nil {
+		return err
+	}
+
+	o[s.text] = c
+	v.SetObject(o)
+	return nil
+}
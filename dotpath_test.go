@@ -0,0 +1,116 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/webern/tcore"
+)
+
+func TestValue_GetPath(t *testing.T) {
+	v := mustParseJSON(t, `{"a": {"b": [10, 20, {"c": "x"}]}, "d.e": {"[f]": true}}`)
+
+	testCases := []struct {
+		Expr     string
+		Expected string
+		Err      error
+	}{
+		{Expr: "a.b[1]", Expected: `20`},
+		{Expr: "a.b[2].c", Expected: `"x"`},
+		{Expr: `["d.e"]["[f]"]`, Expected: `true`},
+		{Expr: "a.b", Expected: `[10, 20, {"c": "x"}]`},
+		{Expr: "a.x", Err: ErrPathNotFound},
+		{Expr: "a.b[3]", Err: ErrPathIndex},
+		{Expr: "a[0]", Err: ErrPathTypeMismatch},
+		{Expr: "a.b.c", Err: ErrPathTypeMismatch},
+		{Expr: "a..b", Err: ErrPathSyntax},
+		{Expr: ".a", Err: ErrPathSyntax},
+		{Expr: "a[x]", Err: ErrPathSyntax},
+		{Expr: "a[1", Err: ErrPathSyntax},
+		{Expr: `a["b]`, Err: ErrPathSyntax},
+	}
+
+	for tcix, tc := range testCases {
+		stm := fmt.Sprintf("test case %d: v.GetPath(%q)", tcix, tc.Expr)
+		actual, err := v.GetPath(tc.Expr)
+
+		if tc.Err != nil {
+			if pathErr, ok := err.(*PathError); !ok || pathErr.Err != tc.Err {
+				t.Errorf("%s - expected a PathError holding '%v' but got '%v'", stm, tc.Err, err)
+			}
+			continue
+		}
+
+		if msg, ok := tcore.TErr(stm, err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		expected := mustParseJSON(t, tc.Expected)
+
+		if msg, ok := tcore.TAssertBool(stm, actual.Equals(expected), true); !ok {
+			t.Error(msg + fmt.Sprintf(" - got %v", actual))
+		}
+	}
+}
+
+func TestValue_SetPath(t *testing.T) {
+	var v Value
+
+	steps := []struct {
+		Expr  string
+		Value Value
+	}{
+		{Expr: "a.b[2].c", Value: NewIntValue(1)},
+		{Expr: "a.b[0]", Value: NewStringValue("first")},
+		{Expr: "a.b[3]", Value: NewBoolValue(true)},
+		{Expr: `a["x.y"]`, Value: NewIntValue(2)},
+		{Expr: "list[1][0]", Value: NewIntValue(3)},
+	}
+
+	for _, step := range steps {
+		if err := v.SetPath(step.Expr, step.Value); err != nil {
+			t.Fatalf("v.SetPath(%q) - %s", step.Expr, err.Error())
+		}
+	}
+
+	expected := mustParseJSON(t, `{
+		"a": {"b": ["first", null, {"c": 1}, true], "x.y": 2},
+		"list": [null, [3]]
+	}`)
+
+	if msg, ok := tcore.TAssertBool("v.Equals(expected)", v.Equals(expected), true); !ok {
+		t.Errorf("%s - got %v", msg, v)
+	}
+
+	before := v.Clone()
+	err := v.SetPath("a.b[0].z[4]", NewIntValue(9))
+
+	if pathErr, ok := err.(*PathError); !ok || pathErr.Err != ErrPathTypeMismatch || pathErr.Index != 3 {
+		t.Errorf("expected a PathError holding a type mismatch at segment 3 but got '%v'", err)
+	}
+
+	if msg, ok := tcore.TAssertBool("v is unchanged after an error", v.Equals(before), true); !ok {
+		t.Error(msg)
+	}
+
+	err = v.SetPath("list[1000000000]", NewIntValue(5))
+
+	if pathErr, ok := err.(*PathError); !ok || pathErr.Err != ErrPathIndex || pathErr.Index != 1 {
+		t.Errorf("expected a PathError holding an index error at segment 1 but got '%v'", err)
+	}
+
+	if msg, ok := tcore.TAssertBool("v is unchanged after an index error", v.Equals(before), true); !ok {
+		t.Error(msg)
+	}
+
+	if err = v.SetPath("list[5].k", NewIntValue(4)); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, _ := v.GetPath("list[5].k"); actual.Int() != 4 || len(v.Object()["list"].Array()) != 6 {
+		t.Errorf("the nested array was not extended in place - got %v", v)
+	}
+}
@@ -0,0 +1,313 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ValueMarshaler is implemented by types that can encode themselves as a Value
+type ValueMarshaler interface {
+	MarshalValue() (Value, error)
+}
+
+// Encode converts a Go value into a Value with reflection, without a round trip through JSON. Numbers of every kind
+// become Int or Float, although a uint64 too large for an int is an error. time.Time becomes Time, structs and maps
+// with string or integer keys become Objects, and slices and arrays become Arrays. Nil pointers, interfaces, maps and
+// slices become Null, and []byte becomes Bytes holding a copy of the slice.
+//
+// Struct fields follow the same rules as in Decode: they are named by their value or json tag, "-" skips a field, and
+// the fields of embedded structs are promoted. The omitempty option leaves out false, zero, empty and nil fields.
+// A type that implements ValueMarshaler encodes itself, and one that implements json.Marshaler or
+// encoding.TextMarshaler is encoded through that method.
+func Encode(x interface{}) (Value, error) {
+	if x == nil {
+		return Value{}, nil
+	}
+
+	e := encoder{seen: make(map[encodeVisit]bool)}
+	return e.encodeValue(Path{}, reflect.ValueOf(x))
+}
+
+// Private
+
+var (
+	valueMarshalerReflectType = reflect.TypeOf((*ValueMarshaler)(nil)).Elem()
+	jsonMarshalerReflectType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerReflectType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	objectReflectType         = reflect.TypeOf(Object{})
+	arrayReflectType          = reflect.TypeOf(Array{})
+)
+
+// encoder holds the state of a single call to Encode
+type encoder struct {
+	// seen holds the pointers, maps and slices being encoded along the current path, so that a cycle is an error
+	// rather than endless recursion
+	seen map[encodeVisit]bool
+}
+
+// encodeVisit identifies a pointer, map or slice. A slice also needs its length, since a shorter slice of the same
+// array is not a cycle.
+type encodeVisit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// enter records rv as being encoded, returning an error when it already is
+func (e *encoder) enter(path Path, rv reflect.Value) (encodeVisit, error) {
+	visit := encodeVisit{ptr: rv.Pointer(), typ: rv.Type()}
+
+	if rv.Kind() == reflect.Slice {
+		visit.len = rv.Len()
+	}
+
+	if e.seen[visit] {
+		return visit, encodeError(path, "encountered a cycle via %s", rv.Type().String())
+	}
+
+	e.seen[visit] = true
+	return visit, nil
+}
+
+func encodeError(path Path, format string, args ...interface{}) error {
+	if len(path) == 0 {
+		return fmt.Errorf("(root): "+format, args...)
+	}
+
+	return fmt.Errorf(path.String()+": "+format, args...)
+}
+
+func (e *encoder) encodeValue(path Path, rv reflect.Value) (Value, error) {
+	if rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return Value{}, nil
+		}
+		if rv.Kind() == reflect.Ptr {
+			visit, err := e.enter(path, rv)
+			if err != nil {
+				return Value{}, err
+			}
+			defer delete(e.seen, visit)
+		}
+		return e.encodeValue(path, rv.Elem())
+	}
+
+	switch rv.Type() {
+	case valueReflectType:
+		return rv.Interface().(Value).Clone(), nil
+	case objectReflectType:
+		return NewObjectValue(rv.Interface().(Object).Clone()), nil
+	case arrayReflectType:
+		return NewArrayValue(rv.Interface().(Array).Clone()), nil
+	case timeReflectType:
+		return NewTimeValue(rv.Interface().(time.Time)), nil
+	}
+
+	if marshaler, ok := implementer(rv, valueMarshalerReflectType); ok {
+		v, err := marshaler.(ValueMarshaler).MarshalValue()
+		if err != nil {
+			return Value{}, encodeError(path, "%s", err.Error())
+		}
+		return v, nil
+	}
+
+	if marshaler, ok := implementer(rv, jsonMarshalerReflectType); ok {
+		b, err := marshaler.(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return Value{}, encodeError(path, "%s", err.Error())
+		}
+		var v Value
+		if err = json.Unmarshal(b, &v); err != nil {
+			return Value{}, encodeError(path, "%s", err.Error())
+		}
+		return v, nil
+	}
+
+	if marshaler, ok := implementer(rv, textMarshalerReflectType); ok {
+		b, err := marshaler.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return Value{}, encodeError(path, "%s", err.Error())
+		}
+		return NewStringValue(string(b)), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return NewBoolValue(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := rv.Int()
+		if int64(int(i)) != i {
+			return Value{}, encodeError(path, "%d overflows int", i)
+		}
+		return NewIntValue(int(i)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		if u > math.MaxInt64 || uint64(int(u)) != u {
+			return Value{}, encodeError(path, "%d overflows int", u)
+		}
+		return NewIntValue(int(u)), nil
+	case reflect.Float32, reflect.Float64:
+		return NewFloatValue(rv.Float()), nil
+	case reflect.String:
+		return NewStringValue(rv.String()), nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return Value{}, nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return NewBytesValue(append([]byte{}, rv.Bytes()...)), nil
+		}
+		visit, err := e.enter(path, rv)
+		if err != nil {
+			return Value{}, err
+		}
+		defer delete(e.seen, visit)
+		return e.encodeArray(path, rv)
+	case reflect.Array:
+		return e.encodeArray(path, rv)
+	case reflect.Map:
+		if rv.IsNil() {
+			return Value{}, nil
+		}
+		visit, err := e.enter(path, rv)
+		if err != nil {
+			return Value{}, err
+		}
+		defer delete(e.seen, visit)
+		return e.encodeMap(path, rv)
+	case reflect.Struct:
+		return e.encodeStruct(path, rv)
+	}
+
+	return Value{}, encodeError(path, "cannot encode %s", rv.Type().String())
+}
+
+// implementer returns rv as the interface type t, using its address when t is implemented with a pointer receiver
+func implementer(rv reflect.Value, t reflect.Type) (interface{}, bool) {
+	if rv.Type().Implements(t) {
+		return rv.Interface(), true
+	}
+
+	if rv.CanAddr() && rv.Addr().Type().Implements(t) {
+		return rv.Addr().Interface(), true
+	}
+
+	return nil, false
+}
+
+func (e *encoder) encodeArray(path Path, rv reflect.Value) (Value, error) {
+	a := make(Array, rv.Len())
+
+	for i := range a {
+		element, err := e.encodeValue(path.Index(i), rv.Index(i))
+
+		if err != nil {
+			return Value{}, err
+		}
+
+		a[i] = element
+	}
+
+	return NewArrayValue(a), nil
+}
+
+func (e *encoder) encodeMap(path Path, rv reflect.Value) (Value, error) {
+	o := NewObject(rv.Len())
+	keys := rv.MapKeys()
+
+	for _, k := range keys {
+		var key string
+
+		switch {
+		case k.Kind() == reflect.String:
+			key = k.String()
+		case k.Type().Implements(textMarshalerReflectType):
+			b, err := k.Interface().(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return Value{}, encodeError(path, "%s", err.Error())
+			}
+			key = string(b)
+		case k.Kind() >= reflect.Int && k.Kind() <= reflect.Int64:
+			key = strconv.FormatInt(k.Int(), 10)
+		case k.Kind() >= reflect.Uint && k.Kind() <= reflect.Uintptr:
+			key = strconv.FormatUint(k.Uint(), 10)
+		default:
+			return Value{}, encodeError(path, "cannot encode a map keyed by %s", k.Type().String())
+		}
+
+		member, err := e.encodeValue(path.Key(key), rv.MapIndex(k))
+
+		if err != nil {
+			return Value{}, err
+		}
+
+		o[key] = member
+	}
+
+	return NewObjectValue(o), nil
+}
+
+func (e *encoder) encodeStruct(path Path, rv reflect.Value) (Value, error) {
+	fields := structFields(rv.Type())
+	o := NewObject(len(fields))
+
+	for _, field := range fields {
+		f, ok := fieldForEncode(rv, field.index)
+
+		if !ok || (field.omitEmpty && isEmptyValue(f)) {
+			continue
+		}
+
+		member, err := e.encodeValue(path.Key(field.name), f)
+
+		if err != nil {
+			return Value{}, err
+		}
+
+		o[field.name] = member
+	}
+
+	return NewObjectValue(o), nil
+}
+
+// fieldForEncode follows an index path through embedded structs, returning false at a nil embedded pointer
+func fieldForEncode(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+
+	return rv, true
+}
+
+// isEmptyValue decides omitempty in the same way as encoding/json
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return rv.IsNil()
+	}
+
+	return false
+}
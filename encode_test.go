@@ -0,0 +1,204 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/webern/tcore"
+)
+
+type encodeTestCelsius float64
+
+func (c encodeTestCelsius) MarshalValue() (Value, error) {
+	return NewStringValue(fmt.Sprintf("%.1fC", float64(c))), nil
+}
+
+type encodeTestFailing struct{}
+
+func (f *encodeTestFailing) MarshalValue() (Value, error) {
+	return Value{}, errors.New("failing")
+}
+
+type encodeTestLevel int
+
+func (l *encodeTestLevel) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("level-%d", int(*l))), nil
+}
+
+type encodeTestMeta struct {
+	Version uint8  `json:"version"`
+	Owner   string `json:"owner,omitempty"`
+}
+
+type encodeTestRecord struct {
+	*encodeTestMeta
+	Name      string            `value:"name" json:"title"`
+	When      time.Time         `json:"when"`
+	Tags      []string          `json:"tags"`
+	Counts    map[int]uint64    `json:"counts"`
+	Temp      encodeTestCelsius `json:"temp"`
+	Parent    *encodeTestRecord `json:"parent"`
+	Note      string            `json:"note,omitempty"`
+	Hidden    string            `json:"-"`
+	IP        net.IP            `json:"ip"`
+	Data      []byte            `json:"data"`
+	Grid      [2][2]int8        `json:"grid"`
+	Any       interface{}       `json:"any"`
+	unexposed int
+}
+
+func TestEncode(t *testing.T) {
+	when := time.Date(2019, 5, 1, 12, 0, 0, 123, time.UTC)
+	record := encodeTestRecord{
+		encodeTestMeta: &encodeTestMeta{Version: 2},
+		Name:           "r",
+		When:           when,
+		Tags:           []string{"a", "b"},
+		Counts:         map[int]uint64{1: 10},
+		Temp:           21.5,
+		Hidden:         "x",
+		IP:             net.ParseIP("10.0.0.1"),
+		Data:           []byte("hi"),
+		Grid:           [2][2]int8{{1, 2}, {3, 4}},
+		Any:            map[string]interface{}{"k": []int{1}},
+		unexposed:      1,
+	}
+
+	actual, err := Encode(&record)
+
+	if msg, ok := tcore.TErr("Encode(&record)", err); !ok {
+		t.Fatal(msg)
+	}
+
+	expected := mustParseJSON(t, `{
+		"version": 2,
+		"name": "r",
+		"tags": ["a", "b"],
+		"counts": {"1": 10},
+		"temp": "21.5C",
+		"parent": null,
+		"ip": "10.0.0.1",
+		"grid": [[1, 2], [3, 4]],
+		"any": {"k": [1]}
+	}`)
+
+	if err = expected.Set("/when", NewTimeValue(when)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = expected.Set("/data", NewBytesValue([]byte("hi"))); err != nil {
+		t.Fatal(err)
+	}
+
+	if msg, ok := tcore.TAssertBool("actual.Equals(expected)", actual.Equals(expected), true); !ok {
+		t.Errorf("%s - got %v", msg, actual)
+	}
+
+	if got := actual.Object()["when"]; !got.IsTime() || !got.Time().Equal(when) {
+		t.Errorf("expected the time to be kept exactly but got %v", got)
+	}
+}
+
+func TestEncode_Scalars(t *testing.T) {
+	testCases := []struct {
+		Input           interface{}
+		Expected        Value
+		IsErrorExpected bool
+	}{
+		{Input: nil, Expected: NewValue()},
+		{Input: uint16(7), Expected: NewIntValue(7)},
+		{Input: int8(-7), Expected: NewIntValue(-7)},
+		{Input: uint64(math.MaxInt64), Expected: NewIntValue(math.MaxInt64)},
+		{Input: uint64(math.MaxUint64), IsErrorExpected: true},
+		{Input: float32(1.5), Expected: NewFloatValue(1.5)},
+		{Input: []string(nil), Expected: NewValue()},
+		{Input: map[float64]int{1: 1}, IsErrorExpected: true},
+		{Input: make(chan int), IsErrorExpected: true},
+		{Input: []interface{}{&encodeTestFailing{}}, IsErrorExpected: true},
+		{Input: NewObject(0), Expected: NewObjectValue(NewObject(0))},
+		{
+			Input:    &struct{ Level encodeTestLevel }{Level: 3},
+			Expected: NewObjectValue(Object{"Level": NewStringValue("level-3")}),
+		},
+	}
+
+	for tcix, tc := range testCases {
+		stm := fmt.Sprintf("test case %d: Encode(%T)", tcix, tc.Input)
+		actual, err := Encode(tc.Input)
+
+		if tc.IsErrorExpected {
+			if err == nil {
+				t.Errorf("%s - an error was expected", stm)
+			}
+			continue
+		}
+
+		if msg, ok := tcore.TErr(stm, err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		if msg, ok := tcore.TAssertBool(stm, actual.Equals(tc.Expected), true); !ok {
+			t.Errorf("%s - got %v", msg, actual)
+		}
+	}
+}
+
+func TestEncode_Cycle(t *testing.T) {
+	type node struct {
+		Name string
+		Next *node
+	}
+
+	n := &node{Name: "a"}
+	n.Next = &node{Name: "b", Next: n}
+	_, err := Encode(n)
+
+	if err == nil || !strings.HasPrefix(err.Error(), "/Next/Next: ") {
+		t.Errorf("expected a cycle error at /Next/Next but got '%v'", err)
+	}
+
+	m := map[string]interface{}{}
+	m["self"] = m
+
+	if _, err = Encode(m); err == nil {
+		t.Error("an error was expected for a map that contains itself")
+	}
+
+	shared := &node{Name: "shared"}
+	actual, err := Encode([]*node{shared, shared})
+
+	if msg, ok := tcore.TErr("Encode of a shared pointer", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertInt("len(actual.Array())", len(actual.Array()), 2); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestEncode_DecodeRoundTrip(t *testing.T) {
+	original := decodeTestBase{ID: 3, Created: time.Date(2019, 5, 1, 0, 0, 0, 5, time.UTC)}
+	v, err := Encode(original)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded decodeTestBase
+
+	if err = Decode(v, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded != original {
+		t.Errorf("expected %v but got %v", original, decoded)
+	}
+}
@@ -0,0 +1,187 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"math"
+	"time"
+)
+
+// EqualOptions controls EqualsWith. The zero value compares numbers and Times exactly, by Type and value.
+type EqualOptions struct {
+	// NumericCrossType lets an Int equal a Float that holds the same number
+	NumericCrossType bool
+
+	// FloatAbsEpsilon and FloatRelEpsilon let numbers differ by up to an absolute amount, or by up to a fraction of the
+	// larger magnitude. Numbers are equal if either tolerance is met. They apply to an Int only when it is compared to
+	// a Float under NumericCrossType.
+	FloatAbsEpsilon float64
+	FloatRelEpsilon float64
+
+	// TimePrecision truncates Times to a multiple of the duration before comparing them, so time.Second compares as
+	// Equals does. Zero compares to the nanosecond.
+	TimePrecision time.Duration
+
+	// TimeZoneSensitive requires Times to be in the same zone, by name and offset, as well as at the same instant
+	TimeZoneSensitive bool
+
+	// NaNEqual lets NaN equal NaN
+	NaNEqual bool
+
+	// IgnorePaths lists JSON Pointers to locations that are left out of the comparison, whether or not they exist on
+	// either side. A segment of * matches any key or index, e.g. /items/*/updated. Malformed pointers are skipped.
+	IgnorePaths []string
+}
+
+// EqualsWith is Equals with options for comparing numbers, Times and NaN more loosely or strictly, and for leaving
+// parts of the trees out of the comparison
+func (v *Value) EqualsWith(other Value, opts EqualOptions) bool {
+	var ignore []Path
+
+	for _, pointer := range opts.IgnorePaths {
+		if path, err := ParsePointer(pointer); err == nil {
+			ignore = append(ignore, path)
+		}
+	}
+
+	e := equality{opts: opts, ignore: ignore}
+	return e.equal(*v, other, Path{})
+}
+
+// Private
+
+type equality struct {
+	opts   EqualOptions
+	ignore []Path
+}
+
+func (e equality) equal(a, b Value, path Path) bool {
+	if e.ignored(path) {
+		return true
+	}
+
+	if isNumber(a) && isNumber(b) {
+		return e.numbersEqual(a, b)
+	}
+
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Type() {
+	case Time:
+		return e.timesEqual(a.Time(), b.Time())
+	case ArrayType:
+		return e.arraysEqual(a.Array(), b.Array(), path)
+	case ObjectType:
+		return e.objectsEqual(a.Object(), b.Object(), path)
+	}
+
+	return a.Equals(b)
+}
+
+func (e equality) ignored(path Path) bool {
+	for _, ignore := range e.ignore {
+		if matchPath(ignore, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isNumber(v Value) bool {
+	return v.IsInt() || v.IsFloat()
+}
+
+func (e equality) numbersEqual(a, b Value) bool {
+	switch {
+	case a.IsInt() && b.IsInt():
+		return a.Int() == b.Int()
+	case a.IsFloat() && b.IsFloat():
+		return e.floatsEqual(a.Float(), b.Float())
+	case !e.opts.NumericCrossType:
+		return false
+	}
+
+	i, f := a, b
+
+	if a.IsFloat() {
+		i, f = b, a
+	}
+
+	if compareIntFloat(i.Int(), f.Float()) == 0 {
+		return true
+	}
+
+	return (e.opts.FloatAbsEpsilon > 0 || e.opts.FloatRelEpsilon > 0) && e.floatsEqual(float64(i.Int()), f.Float())
+}
+
+func (e equality) floatsEqual(a, b float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return e.opts.NaNEqual && math.IsNaN(a) && math.IsNaN(b)
+	}
+
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return a == b
+	}
+
+	if a == b {
+		return true
+	}
+
+	diff := math.Abs(a - b)
+
+	return diff <= e.opts.FloatAbsEpsilon || diff <= e.opts.FloatRelEpsilon*math.Max(math.Abs(a), math.Abs(b))
+}
+
+func (e equality) timesEqual(a, b time.Time) bool {
+	if e.opts.TimeZoneSensitive {
+		aName, aOffset := a.Zone()
+		bName, bOffset := b.Zone()
+		if aName != bName || aOffset != bOffset {
+			return false
+		}
+	}
+
+	if e.opts.TimePrecision > 0 {
+		a = a.Truncate(e.opts.TimePrecision)
+		b = b.Truncate(e.opts.TimePrecision)
+	}
+
+	return a.Equal(b)
+}
+
+func (e equality) arraysEqual(a, b Array, path Path) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !e.equal(a[i], b[i], path.Index(i)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e equality) objectsEqual(a, b Object, path Path) bool {
+	for key, member := range a {
+		other, ok := b[key]
+
+		if e.ignored(path.Key(key)) {
+			continue
+		} else if !ok || !e.equal(member, other, path.Key(key)) {
+			return false
+		}
+	}
+
+	for key := range b {
+		if _, ok := a[key]; !ok && !e.ignored(path.Key(key)) {
+			return false
+		}
+	}
+
+	return true
+}
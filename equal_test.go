@@ -0,0 +1,109 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/webern/tcore"
+)
+
+func TestValue_EqualsWith(t *testing.T) {
+	t0 := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	plusOne := time.FixedZone("PLUS1", 3600)
+	tenth := 0.1 // a variable, so that tenth + 0.2 is computed in float64 rather than as an exact constant
+
+	testCases := []struct {
+		A        Value
+		B        Value
+		Opts     EqualOptions
+		Expected bool
+	}{
+		{A: NewIntValue(1), B: NewFloatValue(1), Expected: false},
+		{A: NewIntValue(1), B: NewFloatValue(1), Opts: EqualOptions{NumericCrossType: true}, Expected: true},
+		{A: NewFloatValue(2), B: NewIntValue(2), Opts: EqualOptions{NumericCrossType: true}, Expected: true},
+		{A: NewIntValue(1), B: NewFloatValue(1.5), Opts: EqualOptions{NumericCrossType: true}, Expected: false},
+		{
+			A:        NewIntValue(1),
+			B:        NewFloatValue(1.05),
+			Opts:     EqualOptions{NumericCrossType: true, FloatAbsEpsilon: 0.1},
+			Expected: true,
+		},
+		{A: NewFloatValue(tenth + 0.2), B: NewFloatValue(0.3), Expected: false},
+		{A: NewFloatValue(tenth + 0.2), B: NewFloatValue(0.3), Opts: EqualOptions{FloatAbsEpsilon: 1e-9}, Expected: true},
+		{A: NewFloatValue(1000), B: NewFloatValue(1001), Opts: EqualOptions{FloatRelEpsilon: 0.01}, Expected: true},
+		{A: NewFloatValue(1000), B: NewFloatValue(1020), Opts: EqualOptions{FloatRelEpsilon: 0.01}, Expected: false},
+		{A: NewFloatValue(math.NaN()), B: NewFloatValue(math.NaN()), Expected: false},
+		{A: NewFloatValue(math.NaN()), B: NewFloatValue(math.NaN()), Opts: EqualOptions{NaNEqual: true}, Expected: true},
+		{A: NewFloatValue(math.Inf(1)), B: NewFloatValue(1), Opts: EqualOptions{FloatAbsEpsilon: 0.1}, Expected: false},
+		{A: NewFloatValue(math.Inf(1)), B: NewFloatValue(1), Opts: EqualOptions{FloatRelEpsilon: 0.1}, Expected: false},
+		{
+			A:        NewFloatValue(math.Inf(1)),
+			B:        NewFloatValue(math.Inf(-1)),
+			Opts:     EqualOptions{FloatAbsEpsilon: 0.1},
+			Expected: false,
+		},
+		{
+			A:        NewFloatValue(math.Inf(1)),
+			B:        NewFloatValue(math.Inf(-1)),
+			Opts:     EqualOptions{FloatRelEpsilon: 0.1},
+			Expected: false,
+		},
+		{
+			A:        NewIntValue(1),
+			B:        NewFloatValue(math.Inf(1)),
+			Opts:     EqualOptions{NumericCrossType: true, FloatRelEpsilon: 0.1},
+			Expected: false,
+		},
+		{
+			A:        NewFloatValue(math.Inf(1)),
+			B:        NewFloatValue(math.Inf(1)),
+			Opts:     EqualOptions{FloatRelEpsilon: 0.1},
+			Expected: true,
+		},
+		{A: NewTimeValue(t0), B: NewTimeValue(t0.Add(time.Millisecond)), Expected: false},
+		{
+			A:        NewTimeValue(t0),
+			B:        NewTimeValue(t0.Add(time.Millisecond)),
+			Opts:     EqualOptions{TimePrecision: time.Second},
+			Expected: true,
+		},
+		{A: NewTimeValue(t0), B: NewTimeValue(t0.In(plusOne)), Expected: true},
+		{A: NewTimeValue(t0), B: NewTimeValue(t0.In(plusOne)), Opts: EqualOptions{TimeZoneSensitive: true}, Expected: false},
+		{
+			A:        mustParseJSON(t, `{"id": 1, "updated": "x", "items": [{"n": 1, "at": 1}, {"n": 2, "at": 2}]}`),
+			B:        mustParseJSON(t, `{"id": 1, "items": [{"n": 1, "at": 5}, {"n": 2}]}`),
+			Opts:     EqualOptions{IgnorePaths: []string{"/updated", "/items/*/at"}},
+			Expected: true,
+		},
+		{
+			A:        mustParseJSON(t, `{"id": 1, "items": [{"n": 1}]}`),
+			B:        mustParseJSON(t, `{"id": 1, "items": [{"n": 2}]}`),
+			Opts:     EqualOptions{IgnorePaths: []string{"/items/*/at"}},
+			Expected: false,
+		},
+		{
+			A:        mustParseJSON(t, `[1, 2.0]`),
+			B:        mustParseJSON(t, `[1.0, 2]`),
+			Opts:     EqualOptions{NumericCrossType: true},
+			Expected: true,
+		},
+		{A: NewStringValue("a"), B: NewStringValue("a"), Expected: true},
+		{A: NewStringValue("1"), B: NewIntValue(1), Opts: EqualOptions{NumericCrossType: true}, Expected: false},
+	}
+
+	for tcix, tc := range testCases {
+		stm := fmt.Sprintf("test case %d: %v.EqualsWith(%v)", tcix, tc.A, tc.B)
+
+		if msg, ok := tcore.TAssertBool(stm, tc.A.EqualsWith(tc.B, tc.Opts), tc.Expected); !ok {
+			t.Error(msg)
+		}
+
+		if msg, ok := tcore.TAssertBool(stm+" reversed", tc.B.EqualsWith(tc.A, tc.Opts), tc.Expected); !ok {
+			t.Error(msg)
+		}
+	}
+}
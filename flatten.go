@@ -0,0 +1,232 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IndexNotation says how array indices are written in flattened keys
+type IndexNotation int
+
+const (
+	IndexSegments IndexNotation = iota // IndexSegments writes indices as ordinary segments, e.g. a.b.0
+	IndexBrackets                      // IndexBrackets writes indices in brackets, e.g. a.b[0]
+)
+
+// FlattenOptions controls FlattenWith
+type FlattenOptions struct {
+	Separator string
+	Indexes   IndexNotation
+}
+
+// UnflattenOptions controls UnflattenWith
+type UnflattenOptions struct {
+	Separator string
+	Indexes   IndexNotation
+
+	// NumericArrays turns each rebuilt Object whose keys are exactly 0 through n-1 into an Array. Otherwise every
+	// container is rebuilt as an Object.
+	NumericArrays bool
+}
+
+// Flatten returns an Object with one entry for every leaf of v, keyed by the leaf's path with segments joined by sep.
+// For example {"a":{"b":[1,2]}} becomes {"a.b.0":1,"a.b.1":2}. Empty Objects and Arrays are kept as leaves. A v that
+// is not an Object or Array is returned as the single entry with the empty key. Keys that contain sep can collide,
+// in which case the key visited last, in sorted order, wins.
+func Flatten(v Value, sep string) Object {
+	return FlattenWith(v, FlattenOptions{Separator: sep})
+}
+
+// FlattenWith is Flatten with options
+func FlattenWith(v Value, opts FlattenOptions) Object {
+	result := NewObject(0)
+	flattenInto(result, "", v, opts)
+	return result
+}
+
+// Unflatten rebuilds a tree of Objects from the output of Flatten, splitting each key on sep. It returns an error when
+// two keys conflict, such as a.b and a.b.c, where a.b would have to be both a leaf and a container. A leaf that holds
+// an Object is still a leaf, so a and a.b also conflict when a holds an Object.
+func Unflatten(o Object, sep string) (Value, error) {
+	return UnflattenWith(o, UnflattenOptions{Separator: sep})
+}
+
+// UnflattenWith is Unflatten with options
+func UnflattenWith(o Object, opts UnflattenOptions) (Value, error) {
+	root := NewObject(len(o))
+
+	// created holds the paths of the Objects made here, as opposed to Object leaves, joined by a NUL byte
+	created := map[string]bool{"": true}
+
+	for _, key := range sortedKeys(o) {
+		segments := splitFlatKey(key, opts)
+		current := root
+
+		for i, segment := range segments[:len(segments)-1] {
+			next, ok := current[segment]
+			path := strings.Join(segments[:i+1], "\x00")
+
+			if !ok {
+				next = NewObjectValue(NewObject(0))
+				current[segment] = next
+				created[path] = true
+			} else if next.Type() != ObjectType || !created[path] {
+				return Value{}, fmt.Errorf("the key %q conflicts with the leaf at %q", key,
+					strings.Join(segments[:i+1], opts.Separator))
+			}
+
+			current = next.Object()
+		}
+
+		last := segments[len(segments)-1]
+
+		if _, ok := current[last]; ok {
+			return Value{}, fmt.Errorf("the key %q conflicts with another key", key)
+		}
+
+		current[last] = o[key].Clone()
+	}
+
+	result := NewObjectValue(root)
+
+	if opts.NumericArrays {
+		result = numericArrays(result, "", created)
+	}
+
+	return result, nil
+}
+
+// Private
+
+func flattenInto(result Object, prefix string, v Value, opts FlattenOptions) {
+	switch {
+	case v.Type() == ObjectType && len(v.Object()) > 0:
+		o := v.Object()
+		for _, key := range sortedKeys(o) {
+			flattenInto(result, joinFlatKey(prefix, key, opts.Separator), o[key], opts)
+		}
+	case v.Type() == ArrayType && len(v.Array()) > 0:
+		for i, element := range v.Array() {
+			if opts.Indexes == IndexBrackets {
+				flattenInto(result, prefix+"["+strconv.Itoa(i)+"]", element, opts)
+			} else {
+				flattenInto(result, joinFlatKey(prefix, strconv.Itoa(i), opts.Separator), element, opts)
+			}
+		}
+	default:
+		result[prefix] = v.Clone()
+	}
+}
+
+func joinFlatKey(prefix, segment, sep string) string {
+	if prefix == "" {
+		return segment
+	}
+
+	return prefix + sep + segment
+}
+
+// splitFlatKey splits a flattened key into its segments. With IndexBrackets, trailing [n] groups on each part become
+// segments of their own.
+func splitFlatKey(key string, opts UnflattenOptions) []string {
+	var parts []string
+
+	if opts.Separator == "" {
+		parts = []string{key}
+	} else {
+		parts = strings.Split(key, opts.Separator)
+	}
+
+	if opts.Indexes != IndexBrackets {
+		return parts
+	}
+
+	var segments []string
+
+	for _, part := range parts {
+		open := strings.IndexByte(part, '[')
+
+		if open < 0 {
+			segments = append(segments, part)
+			continue
+		}
+
+		indexes, ok := parseBracketIndexes(part[open:])
+
+		if !ok {
+			segments = append(segments, part)
+			continue
+		}
+
+		if open > 0 {
+			segments = append(segments, part[:open])
+		}
+
+		segments = append(segments, indexes...)
+	}
+
+	return segments
+}
+
+// parseBracketIndexes parses a sequence like [0][12] into its indices
+func parseBracketIndexes(s string) ([]string, bool) {
+	var indexes []string
+
+	for len(s) > 0 {
+		end := strings.IndexByte(s, ']')
+
+		if s[0] != '[' || end < 2 {
+			return nil, false
+		}
+
+		if _, err := strconv.Atoi(s[1:end]); err != nil {
+			return nil, false
+		}
+
+		indexes = append(indexes, s[1:end])
+		s = s[end+1:]
+	}
+
+	return indexes, true
+}
+
+// numericArrays converts the Objects that UnflattenWith created and that are keyed by 0 through n-1 into Arrays,
+// working from the leaves up. Object leaves are left as they are.
+func numericArrays(v Value, path string, created map[string]bool) Value {
+	if v.Type() != ObjectType || !created[path] {
+		return v
+	}
+
+	o := v.Object()
+
+	for key, member := range o {
+		memberPath := key
+
+		if path != "" {
+			memberPath = path + "\x00" + key
+		}
+
+		o[key] = numericArrays(member, memberPath, created)
+	}
+
+	if len(o) == 0 {
+		return v
+	}
+
+	a := make(Array, len(o))
+
+	for i := range a {
+		member, ok := o[strconv.Itoa(i)]
+
+		if !ok {
+			return v
+		}
+
+		a[i] = member
+	}
+
+	return NewArrayValue(a)
+}
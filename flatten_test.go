@@ -0,0 +1,150 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/webern/tcore"
+)
+
+func TestFlatten(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Opts     FlattenOptions
+		Expected string
+	}{
+		{
+			Input:    `{"a":{"b":[1,2]}}`,
+			Opts:     FlattenOptions{Separator: "."},
+			Expected: `{"a.b.0":1,"a.b.1":2}`,
+		},
+		{
+			Input:    `{"a":{"b":[1,{"c":true}]},"d":"x"}`,
+			Opts:     FlattenOptions{Separator: ".", Indexes: IndexBrackets},
+			Expected: `{"a.b[0]":1,"a.b[1].c":true,"d":"x"}`,
+		},
+		{
+			Input:    `{"a":{},"b":[],"c":null}`,
+			Opts:     FlattenOptions{Separator: "_"},
+			Expected: `{"a":{},"b":[],"c":null}`,
+		},
+		{
+			Input:    `[[1],[2,3]]`,
+			Opts:     FlattenOptions{Separator: "/", Indexes: IndexBrackets},
+			Expected: `{"[0][0]":1,"[1][0]":2,"[1][1]":3}`,
+		},
+		{
+			Input:    `42`,
+			Opts:     FlattenOptions{Separator: "."},
+			Expected: `{"":42}`,
+		},
+	}
+
+	for tcix, tc := range testCases {
+		stm := fmt.Sprintf("test case %d: FlattenWith(%s)", tcix, tc.Input)
+		actual := NewObjectValue(FlattenWith(mustParseJSON(t, tc.Input), tc.Opts))
+		expected := mustParseJSON(t, tc.Expected)
+
+		if msg, ok := tcore.TAssertBool(stm, actual.Equals(expected), true); !ok {
+			t.Error(msg + fmt.Sprintf(" - got %v", actual))
+		}
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	testCases := []struct {
+		Input           string
+		Opts            UnflattenOptions
+		Expected        string
+		IsErrorExpected bool
+	}{
+		{
+			Input:    `{"a.b.0":1,"a.b.1":2}`,
+			Opts:     UnflattenOptions{Separator: "."},
+			Expected: `{"a":{"b":{"0":1,"1":2}}}`,
+		},
+		{
+			Input:    `{"a.b.0":1,"a.b.1":2,"c.1":3}`,
+			Opts:     UnflattenOptions{Separator: ".", NumericArrays: true},
+			Expected: `{"a":{"b":[1,2]},"c":{"1":3}}`,
+		},
+		{
+			Input:    `{"a.b[0]":1,"a.b[1].c":true,"d":"x"}`,
+			Opts:     UnflattenOptions{Separator: ".", Indexes: IndexBrackets, NumericArrays: true},
+			Expected: `{"a":{"b":[1,{"c":true}]},"d":"x"}`,
+		},
+		{
+			Input:    `{"a":{"0":1},"b.0":{"0":2}}`,
+			Opts:     UnflattenOptions{Separator: ".", NumericArrays: true},
+			Expected: `{"a":{"0":1},"b":[{"0":2}]}`,
+		},
+		{
+			Input:    `{"a":{},"b":[]}`,
+			Opts:     UnflattenOptions{Separator: "."},
+			Expected: `{"a":{},"b":[]}`,
+		},
+		{
+			Input:           `{"a.b":1,"a.b.c":2}`,
+			Opts:            UnflattenOptions{Separator: "."},
+			IsErrorExpected: true,
+		},
+		{
+			Input:           `{"a":1,"a.b":2}`,
+			Opts:            UnflattenOptions{Separator: "."},
+			IsErrorExpected: true,
+		},
+		{
+			Input:           `{"a":{"x":1},"a.b":2}`,
+			Opts:            UnflattenOptions{Separator: "."},
+			IsErrorExpected: true,
+		},
+		{
+			Input:           `{"a":{},"a.b":2}`,
+			Opts:            UnflattenOptions{Separator: "."},
+			IsErrorExpected: true,
+		},
+	}
+
+	for tcix, tc := range testCases {
+		stm := fmt.Sprintf("test case %d: UnflattenWith(%s)", tcix, tc.Input)
+		actual, err := UnflattenWith(mustParseJSON(t, tc.Input).Object(), tc.Opts)
+
+		if tc.IsErrorExpected {
+			if err == nil {
+				t.Errorf("%s - an error was expected", stm)
+			}
+			continue
+		}
+
+		if msg, ok := tcore.TErr(stm, err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		expected := mustParseJSON(t, tc.Expected)
+
+		if msg, ok := tcore.TAssertBool(stm, actual.Equals(expected), true); !ok {
+			t.Error(msg + fmt.Sprintf(" - got %v", actual))
+		}
+	}
+}
+
+func TestFlatten_RoundTrip(t *testing.T) {
+	v := mustParseJSON(t, `{"a":{"b":[1,{"c":[true,null]}],"d":{}},"e":"x"}`)
+
+	for _, notation := range []IndexNotation{IndexSegments, IndexBrackets} {
+		flat := FlattenWith(v, FlattenOptions{Separator: ".", Indexes: notation})
+		actual, err := UnflattenWith(flat, UnflattenOptions{Separator: ".", Indexes: notation, NumericArrays: true})
+
+		if msg, ok := tcore.TErr("UnflattenWith", err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		if msg, ok := tcore.TAssertBool("actual.Equals(v)", actual.Equals(v), true); !ok {
+			t.Error(msg + fmt.Sprintf(" - got %v", actual))
+		}
+	}
+}
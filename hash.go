@@ -0,0 +1,363 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"sort"
+)
+
+// Hash returns a 64-bit FNV-1a hash of the Value that is consistent with Equals: Values that are equal have the same
+// hash. Time is hashed by its Unix seconds, as Equals compares it, and the members of an Object are combined in a way
+// that does not depend on map order. A Float that holds a whole number hashes like the Int of the same value, so the
+// hash can also key Values whose numbers are compared across types.
+func (v Value) Hash() uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+
+	writeUint := func(u uint64) {
+		binary.BigEndian.PutUint64(buf[:], u)
+		_, _ = h.Write(buf[:])
+	}
+
+	switch v.Type() {
+	case Bool:
+		if v.Bool() {
+			_, _ = h.Write([]byte{byte(Bool), 1})
+		} else {
+			_, _ = h.Write([]byte{byte(Bool), 0})
+		}
+	case Int:
+		_, _ = h.Write([]byte{byte(Int)})
+		writeUint(uint64(v.Int()))
+	case Float:
+		f := v.Float()
+		if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			_, _ = h.Write([]byte{byte(Int)})
+			writeUint(uint64(int64(f)))
+		} else {
+			_, _ = h.Write([]byte{byte(Float)})
+			writeUint(math.Float64bits(f))
+		}
+	case String:
+		_, _ = h.Write([]byte{byte(String)})
+		_, _ = h.Write([]byte(v.String()))
+	case Bytes:
+		_, _ = h.Write([]byte{byte(Bytes)})
+		_, _ = h.Write(v.Bytes())
+	case Time:
+		_, _ = h.Write([]byte{byte(Time)})
+		writeUint(uint64(v.Time().Unix()))
+	case ArrayType:
+		_, _ = h.Write([]byte{byte(ArrayType)})
+		for _, element := range v.Array() {
+			writeUint(element.Hash())
+		}
+	case ObjectType:
+		var sum uint64
+		for key, member := range v.Object() {
+			sum += hashMember(key, member)
+		}
+		_, _ = h.Write([]byte{byte(ObjectType)})
+		writeUint(sum)
+	default:
+		_, _ = h.Write([]byte{byte(Null)})
+	}
+
+	return h.Sum64()
+}
+
+// ValueSet is a set of Values, with membership decided by Equals. The zero value is an empty set ready to use.
+//
+// Because membership follows Equals and not Compare, Int 1 and Float 1.0 are separate members even though Compare
+// treats them as equal, and two Times in the same second are the same member even though Compare orders them.
+type ValueSet struct {
+	buckets map[uint64]Array
+	length  int
+}
+
+// NewValueSet returns a set holding the given Values
+func NewValueSet(values ...Value) *ValueSet {
+	s := &ValueSet{}
+
+	for _, v := range values {
+		s.Add(v)
+	}
+
+	return s
+}
+
+// Add stores a copy of v in the set. It returns false if the set already held an equal Value.
+func (s *ValueSet) Add(v Value) bool {
+	h := v.Hash()
+
+	if s.find(h, v) >= 0 {
+		return false
+	}
+
+	if s.buckets == nil {
+		s.buckets = make(map[uint64]Array)
+	}
+
+	s.buckets[h] = append(s.buckets[h], v.Clone())
+	s.length++
+	return true
+}
+
+// Has returns true if the set holds a Value equal to v
+func (s *ValueSet) Has(v Value) bool {
+	return s.find(v.Hash(), v) >= 0
+}
+
+// Remove deletes the Value equal to v from the set. It returns false if there was none.
+func (s *ValueSet) Remove(v Value) bool {
+	h := v.Hash()
+	i := s.find(h, v)
+
+	if i < 0 {
+		return false
+	}
+
+	bucket := s.buckets[h]
+
+	if len(bucket) == 1 {
+		delete(s.buckets, h)
+	} else {
+		s.buckets[h] = append(bucket[:i], bucket[i+1:]...)
+	}
+
+	s.length--
+	return true
+}
+
+// Len returns the number of Values in the set
+func (s *ValueSet) Len() int {
+	return s.length
+}
+
+// Values returns the members of the set, sorted by Compare, with an Int before a Float of the same value
+func (s *ValueSet) Values() Array {
+	values := make(Array, 0, s.length)
+
+	for _, bucket := range s.buckets {
+		values = append(values, bucket...)
+	}
+
+	sortMembers(values)
+	return values
+}
+
+// Union returns a new set holding the Values that are in s, other or both
+func (s *ValueSet) Union(other *ValueSet) *ValueSet {
+	result := NewValueSet(s.Values()...)
+
+	for _, v := range other.Values() {
+		result.Add(v)
+	}
+
+	return result
+}
+
+// Intersection returns a new set holding the Values that are in both s and other
+func (s *ValueSet) Intersection(other *ValueSet) *ValueSet {
+	result := NewValueSet()
+
+	for _, v := range s.Values() {
+		if other.Has(v) {
+			result.Add(v)
+		}
+	}
+
+	return result
+}
+
+// Difference returns a new set holding the Values that are in s but not in other
+func (s *ValueSet) Difference(other *ValueSet) *ValueSet {
+	result := NewValueSet()
+
+	for _, v := range s.Values() {
+		if !other.Has(v) {
+			result.Add(v)
+		}
+	}
+
+	return result
+}
+
+// ValueMap maps Values to arbitrary values, with keys matched by Equals. The zero value is an empty map ready to use.
+//
+// As with ValueSet, Int 1 and Float 1.0 are separate keys even though Compare treats them as equal.
+type ValueMap struct {
+	buckets map[uint64][]valueMapEntry
+	length  int
+}
+
+// NewValueMap returns an empty ValueMap
+func NewValueMap() *ValueMap {
+	return &ValueMap{}
+}
+
+// Set stores value under a copy of key, replacing any value stored under an equal key
+func (m *ValueMap) Set(key Value, value interface{}) {
+	h := key.Hash()
+
+	if i := m.find(h, key); i >= 0 {
+		m.buckets[h][i].value = value
+		return
+	}
+
+	if m.buckets == nil {
+		m.buckets = make(map[uint64][]valueMapEntry)
+	}
+
+	m.buckets[h] = append(m.buckets[h], valueMapEntry{key: key.Clone(), value: value})
+	m.length++
+}
+
+// Get returns the value stored under a key equal to key, and false if there is none
+func (m *ValueMap) Get(key Value) (interface{}, bool) {
+	h := key.Hash()
+
+	if i := m.find(h, key); i >= 0 {
+		return m.buckets[h][i].value, true
+	}
+
+	return nil, false
+}
+
+// Delete removes the entry whose key equals key. It returns false if there was none.
+func (m *ValueMap) Delete(key Value) bool {
+	h := key.Hash()
+	i := m.find(h, key)
+
+	if i < 0 {
+		return false
+	}
+
+	bucket := m.buckets[h]
+
+	if len(bucket) == 1 {
+		delete(m.buckets, h)
+	} else {
+		m.buckets[h] = append(bucket[:i], bucket[i+1:]...)
+	}
+
+	m.length--
+	return true
+}
+
+// Len returns the number of entries in the map
+func (m *ValueMap) Len() int {
+	return m.length
+}
+
+// Keys returns the keys of the map, sorted by Compare, with an Int before a Float of the same value
+func (m *ValueMap) Keys() Array {
+	keys := make(Array, 0, m.length)
+
+	for _, bucket := range m.buckets {
+		for _, entry := range bucket {
+			keys = append(keys, entry.key)
+		}
+	}
+
+	sortMembers(keys)
+	return keys
+}
+
+// KeySet returns the keys of the map as a ValueSet, for use with Union, Intersection and Difference
+func (m *ValueMap) KeySet() *ValueSet {
+	return NewValueSet(m.Keys()...)
+}
+
+// Range calls fn for each entry in the order of Keys, stopping early if fn returns false
+func (m *ValueMap) Range(fn func(key Value, value interface{}) bool) {
+	for _, key := range m.Keys() {
+		value, _ := m.Get(key)
+
+		if !fn(key, value) {
+			return
+		}
+	}
+}
+
+// Private
+
+type valueMapEntry struct {
+	key   Value
+	value interface{}
+}
+
+func hashMember(key string, member Value) uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], member.Hash())
+	_, _ = h.Write([]byte(key))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write(buf[:])
+	return h.Sum64()
+}
+
+func (s *ValueSet) find(h uint64, v Value) int {
+	bucket := s.buckets[h]
+
+	for i := range bucket {
+		if bucket[i].Equals(v) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (m *ValueMap) find(h uint64, key Value) int {
+	bucket := m.buckets[h]
+
+	for i := range bucket {
+		if bucket[i].key.Equals(key) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// sortMembers sorts by Compare and breaks ties with compareTyped, so that members that Compare as equal, such as Int 1
+// and Float 1.0 or [1] and [1.0], always come out in the same order
+func sortMembers(a Array) {
+	sort.Slice(a, func(i, j int) bool {
+		return compareTyped(a[i], a[j]) < 0
+	})
+}
+
+// compareTyped is Compare, except that Values which Compare as equal are then ordered by Type, at every depth
+func compareTyped(a, b Value) int {
+	if c := Compare(a, b); c != 0 {
+		return c
+	}
+
+	if a.Type() != b.Type() {
+		return compareInts(int(a.Type()), int(b.Type()))
+	}
+
+	switch a.Type() {
+	case ArrayType:
+		x, y := a.Array(), b.Array()
+		for i := range x {
+			if c := compareTyped(x[i], y[i]); c != 0 {
+				return c
+			}
+		}
+	case ObjectType:
+		x, y := a.Object(), b.Object()
+		for _, key := range sortedKeys(x) {
+			if c := compareTyped(x[key], y[key]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,153 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/webern/tcore"
+)
+
+func TestValue_Hash(t *testing.T) {
+	t0 := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		A              Value
+		B              Value
+		IsSameExpected bool
+	}{
+		{A: NewValue(), B: NewValue(), IsSameExpected: true},
+		{A: NewIntValue(1), B: NewIntValue(1), IsSameExpected: true},
+		{A: NewIntValue(1), B: NewFloatValue(1), IsSameExpected: true},
+		{A: NewFloatValue(0), B: NewFloatValue(math.Copysign(0, -1)), IsSameExpected: true},
+		{A: NewTimeValue(t0), B: NewTimeValue(t0.Add(time.Millisecond)), IsSameExpected: true},
+		{
+			A:              mustParseJSON(t, `{"a": 1, "b": [true, "x"]}`),
+			B:              mustParseJSON(t, `{"b": [true, "x"], "a": 1}`),
+			IsSameExpected: true,
+		},
+		{A: NewIntValue(1), B: NewIntValue(2)},
+		{A: NewIntValue(1), B: NewFloatValue(1.5)},
+		{A: NewIntValue(0), B: NewBoolValue(false)},
+		{A: NewValue(), B: NewStringValue("")},
+		{A: NewStringValue("1"), B: NewIntValue(1)},
+		{A: mustParseJSON(t, `[1, 2]`), B: mustParseJSON(t, `[2, 1]`)},
+		{A: mustParseJSON(t, `{"a": 1, "b": 2}`), B: mustParseJSON(t, `{"a": 2, "b": 1}`)},
+	}
+
+	for tcix, tc := range testCases {
+		stm := fmt.Sprintf("test case %d: %v.Hash() == %v.Hash()", tcix, tc.A, tc.B)
+
+		if msg, ok := tcore.TAssertBool(stm, tc.A.Hash() == tc.B.Hash(), tc.IsSameExpected); !ok {
+			t.Error(msg)
+		}
+	}
+}
+
+func TestValueSet(t *testing.T) {
+	s := NewValueSet(NewIntValue(1), NewStringValue("a"), mustParseJSON(t, `{"k": [1]}`))
+
+	if msg, ok := tcore.TAssertBool("s.Add(duplicate)", s.Add(mustParseJSON(t, `{"k": [1]}`)), false); !ok {
+		t.Error(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("s.Add(Float 1)", s.Add(NewFloatValue(1)), true); !ok {
+		t.Error(msg)
+	}
+
+	if msg, ok := tcore.TAssertInt("s.Len()", s.Len(), 4); !ok {
+		t.Error(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("s.Remove(Float 1)", s.Remove(NewFloatValue(1)), true); !ok {
+		t.Error(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("s.Has(Int 1)", s.Has(NewIntValue(1)), true); !ok {
+		t.Error(msg)
+	}
+
+	other := NewValueSet(NewStringValue("a"), NewStringValue("b"))
+	testCases := []struct {
+		Name     string
+		Set      *ValueSet
+		Expected string
+	}{
+		{Name: "Union", Set: s.Union(other), Expected: `[1, "a", "b", {"k": [1]}]`},
+		{Name: "Intersection", Set: s.Intersection(other), Expected: `["a"]`},
+		{Name: "Difference", Set: s.Difference(other), Expected: `[1, {"k": [1]}]`},
+	}
+
+	for _, tc := range testCases {
+		expected := mustParseJSON(t, tc.Expected).Array()
+
+		if msg, ok := tcore.TAssertBool(tc.Name, ArraysEqual(tc.Set.Values(), expected), true); !ok {
+			t.Errorf("%s - got %v", msg, tc.Set.Values())
+		}
+	}
+
+	nested := NewValueSet()
+	ints, floats := NewArrayValue(Array{NewIntValue(1)}), NewArrayValue(Array{NewFloatValue(1)})
+
+	for i := 0; i < 20; i++ {
+		nested.Add(NewObjectValue(Object{"a": floats}))
+		nested.Add(NewObjectValue(Object{"a": ints}))
+		nested.Add(floats)
+		nested.Add(ints)
+	}
+
+	expected := Array{ints, floats, NewObjectValue(Object{"a": ints}), NewObjectValue(Object{"a": floats})}
+
+	if msg, ok := tcore.TAssertBool("nested.Values()", ArraysEqual(nested.Values(), expected), true); !ok {
+		t.Errorf("%s - got %v", msg, nested.Values())
+	}
+
+	var zero ValueSet
+
+	if msg, ok := tcore.TAssertBool("zero.Has", zero.Has(NewValue()), false); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestValueMap(t *testing.T) {
+	m := NewValueMap()
+	m.Set(mustParseJSON(t, `["us", 2019]`), 10)
+	m.Set(mustParseJSON(t, `["eu", 2019]`), 20)
+	m.Set(mustParseJSON(t, `["us", 2019]`), 30)
+
+	if msg, ok := tcore.TAssertInt("m.Len()", m.Len(), 2); !ok {
+		t.Error(msg)
+	}
+
+	got, ok := m.Get(mustParseJSON(t, `["us", 2019]`))
+
+	if !ok || got.(int) != 30 {
+		t.Errorf("expected 30 but got %v", got)
+	}
+
+	var keys []string
+
+	m.Range(func(key Value, value interface{}) bool {
+		keys = append(keys, key.Array()[0].String())
+		return true
+	})
+
+	if msg, ok := tcore.TAssertString("keys", fmt.Sprint(keys), "[eu us]"); !ok {
+		t.Error(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("m.KeySet().Has", m.KeySet().Has(mustParseJSON(t, `["eu", 2019]`)), true); !ok {
+		t.Error(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("m.Delete", m.Delete(mustParseJSON(t, `["eu", 2019]`)), true); !ok {
+		t.Error(msg)
+	}
+
+	if _, ok = m.Get(mustParseJSON(t, `["eu", 2019]`)); ok {
+		t.Error("the deleted key was found")
+	}
+}
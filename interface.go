@@ -0,0 +1,67 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+// InterfaceOptions controls InterfaceWith
+type InterfaceOptions struct {
+	// TimeFormat, when set, turns each Time into a string in this layout, e.g. time.RFC3339Nano
+	TimeFormat string
+
+	// UseJSONNumber turns each Int and finite Float into a json.Number, as a json.Decoder does with UseNumber
+	UseJSONNumber bool
+}
+
+// Interface returns v as plain Go values, the inverse of NewValueFromMystery: an Object becomes a
+// map[string]interface{}, an Array a []interface{}, Null nil, Bool a bool, Int an int, Float a float64, String a
+// string, Bytes a []byte and Time a time.Time. The result shares nothing with v.
+func (v Value) Interface() interface{} {
+	return v.InterfaceWith(InterfaceOptions{})
+}
+
+// InterfaceWith is Interface with options
+func (v Value) InterfaceWith(opts InterfaceOptions) interface{} {
+	switch v.Type() {
+	case Bool:
+		return v.Bool()
+	case Int:
+		if opts.UseJSONNumber {
+			return json.Number(strconv.Itoa(v.Int()))
+		}
+		return v.Int()
+	case Float:
+		f := v.Float()
+		if opts.UseJSONNumber && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+		}
+		return f
+	case String:
+		return v.String()
+	case Bytes:
+		return append([]byte{}, v.Bytes()...)
+	case Time:
+		if opts.TimeFormat != "" {
+			return v.Time().Format(opts.TimeFormat)
+		}
+		return v.Time()
+	case ObjectType:
+		m := make(map[string]interface{}, len(v.Object()))
+		for key, member := range v.Object() {
+			m[key] = member.InterfaceWith(opts)
+		}
+		return m
+	case ArrayType:
+		a := make([]interface{}, len(v.Array()))
+		for i, element := range v.Array() {
+			a[i] = element.InterfaceWith(opts)
+		}
+		return a
+	}
+
+	return nil
+}
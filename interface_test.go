@@ -0,0 +1,71 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/webern/tcore"
+)
+
+func TestValue_Interface(t *testing.T) {
+	when := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mustParseJSON(t, `{"a": [1, 2.5, "s", true, null], "b": {"c": {}}}`)
+
+	if err := v.Set("/when", NewTimeValue(when)); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name     string
+		Opts     InterfaceOptions
+		Expected interface{}
+	}{
+		{
+			Name: "default",
+			Expected: map[string]interface{}{
+				"a":    []interface{}{1, 2.5, "s", true, nil},
+				"b":    map[string]interface{}{"c": map[string]interface{}{}},
+				"when": when,
+			},
+		},
+		{
+			Name: "TimeFormat and UseJSONNumber",
+			Opts: InterfaceOptions{TimeFormat: time.RFC3339, UseJSONNumber: true},
+			Expected: map[string]interface{}{
+				"a":    []interface{}{json.Number("1"), json.Number("2.5"), "s", true, nil},
+				"b":    map[string]interface{}{"c": map[string]interface{}{}},
+				"when": "2019-05-01T12:00:00Z",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		actual := v.InterfaceWith(tc.Opts)
+
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Errorf("%s - got %#v, want %#v", tc.Name, actual, tc.Expected)
+		}
+	}
+
+	if got := NewFloatValue(math.Inf(1)).InterfaceWith(InterfaceOptions{UseJSONNumber: true}); got != math.Inf(1) {
+		t.Errorf("expected an infinite float64 but got %#v", got)
+	}
+}
+
+func TestValue_Interface_RoundTrip(t *testing.T) {
+	v := mustParseJSON(t, `{"a": [1, 2.5, "s", true, null], "b": {"c": {"d": []}}}`)
+	actual, err := NewValueFromMystery(v.Interface())
+
+	if msg, ok := tcore.TErr("NewValueFromMystery(v.Interface())", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("actual.Equals(v)", actual.Equals(v), true); !ok {
+		t.Errorf("%s - got %v", msg, actual)
+	}
+}
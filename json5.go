@@ -0,0 +1,599 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// JSON5SyntaxError describes malformed JSON5 (or JSONC) input and the byte offset at which it was found
+type JSON5SyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *JSON5SyntaxError) Error() string {
+	return fmt.Sprintf("json5: %s at offset %d", e.Msg, e.Offset)
+}
+
+// ParseJSON5 parses JSON5 text into a Value. JSON5 is a superset of JSON that allows comments, trailing commas,
+// single-quoted strings, unquoted object keys, hexadecimal numbers, leading or trailing decimal points, explicit plus
+// signs, Infinity and NaN. Plain JSON parses to the same Value tree that json.Unmarshal would produce.
+func ParseJSON5(data []byte) (Value, error) {
+	p := json5Parser{scanner: json5Scanner{data: data}}
+
+	if err := p.advance(); err != nil {
+		return Value{}, err
+	}
+
+	v, err := p.parseValue()
+
+	if err != nil {
+		return Value{}, err
+	}
+
+	if p.tok.kind != json5EOF {
+		return Value{}, p.unexpected()
+	}
+
+	return v, nil
+}
+
+// UnmarshalJSON5 replaces the contents of v with the Value parsed from JSON5 text. On error v is left unchanged.
+func (v *Value) UnmarshalJSON5(data []byte) error {
+	parsed, err := ParseJSON5(data)
+
+	if err != nil {
+		return err
+	}
+
+	*v = parsed
+	return nil
+}
+
+// Private
+
+type json5TokenKind int
+
+const (
+	json5EOF json5TokenKind = iota
+	json5LeftBrace
+	json5RightBrace
+	json5LeftBracket
+	json5RightBracket
+	json5Colon
+	json5Comma
+	json5String // a quoted string, text holds the unescaped contents
+	json5Number // a numeric literal, text holds the literal as written
+	json5Ident  // an unquoted identifier such as true, false, null or an object key
+)
+
+var json5TokenNames = map[json5TokenKind]string{
+	json5EOF:          "end of input",
+	json5LeftBrace:    "'{'",
+	json5RightBrace:   "'}'",
+	json5LeftBracket:  "'['",
+	json5RightBracket: "']'",
+	json5Colon:        "':'",
+	json5Comma:        "','",
+	json5String:       "string",
+	json5Number:       "number",
+	json5Ident:        "identifier",
+}
+
+type json5Token struct {
+	kind  json5TokenKind
+	start int
+	end   int
+	text  string
+}
+
+// json5Scanner splits JSON5 text into tokens, skipping whitespace and comments
+type json5Scanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *json5Scanner) syntaxError(offset int, format string, args ...interface{}) error {
+	return &JSON5SyntaxError{Offset: offset, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (s *json5Scanner) peekRune(offset int) (rune, int) {
+	if offset >= len(s.data) {
+		return utf8.RuneError, 0
+	}
+
+	return utf8.DecodeRune(s.data[offset:])
+}
+
+// skipSpace advances past whitespace, line comments and block comments
+func (s *json5Scanner) skipSpace() error {
+	for s.pos < len(s.data) {
+		r, size := s.peekRune(s.pos)
+
+		if isJSON5Space(r) {
+			s.pos += size
+			continue
+		}
+
+		if r != '/' || s.pos+1 >= len(s.data) {
+			return nil
+		}
+
+		switch s.data[s.pos+1] {
+		case '/':
+			s.pos += 2
+			for s.pos < len(s.data) {
+				r, size = s.peekRune(s.pos)
+				if isJSON5LineTerminator(r) {
+					break
+				}
+				s.pos += size
+			}
+		case '*':
+			start := s.pos
+			end := strings.Index(string(s.data[s.pos+2:]), "*/")
+			if end < 0 {
+				return s.syntaxError(start, "unterminated block comment")
+			}
+			s.pos += 2 + end + 2
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (s *json5Scanner) next() (json5Token, error) {
+	if err := s.skipSpace(); err != nil {
+		return json5Token{}, err
+	}
+
+	start := s.pos
+
+	if s.pos >= len(s.data) {
+		return json5Token{kind: json5EOF, start: start, end: start}, nil
+	}
+
+	punctuation := map[byte]json5TokenKind{
+		'{': json5LeftBrace,
+		'}': json5RightBrace,
+		'[': json5LeftBracket,
+		']': json5RightBracket,
+		':': json5Colon,
+		',': json5Comma,
+	}
+
+	c := s.data[s.pos]
+
+	if kind, ok := punctuation[c]; ok {
+		s.pos++
+		return json5Token{kind: kind, start: start, end: s.pos}, nil
+	}
+
+	switch {
+	case c == '"' || c == '\'':
+		return s.scanString()
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return s.scanNumber()
+	}
+
+	r, _ := s.peekRune(s.pos)
+
+	if isJSON5IdentStart(r) {
+		return s.scanIdent()
+	}
+
+	return json5Token{}, s.syntaxError(start, "unexpected character %q", r)
+}
+
+func (s *json5Scanner) scanString() (json5Token, error) {
+	start := s.pos
+	quote := rune(s.data[s.pos])
+	s.pos++
+	b := strings.Builder{}
+
+	for {
+		if s.pos >= len(s.data) {
+			return json5Token{}, s.syntaxError(start, "unterminated string")
+		}
+
+		r, size := s.peekRune(s.pos)
+
+		if r == quote {
+			s.pos += size
+			return json5Token{kind: json5String, start: start, end: s.pos, text: b.String()}, nil
+		}
+
+		if r == '\n' || r == '\r' {
+			return json5Token{}, s.syntaxError(s.pos, "unescaped line terminator in string")
+		}
+
+		if r != '\\' {
+			b.WriteRune(r)
+			s.pos += size
+			continue
+		}
+
+		if err := s.scanEscape(&b); err != nil {
+			return json5Token{}, err
+		}
+	}
+}
+
+// scanEscape consumes a backslash escape sequence and writes the character it represents
+func (s *json5Scanner) scanEscape(b *strings.Builder) error {
+	escapeStart := s.pos
+	s.pos++
+
+	if s.pos >= len(s.data) {
+		return s.syntaxError(escapeStart, "unterminated escape sequence")
+	}
+
+	r, size := s.peekRune(s.pos)
+	s.pos += size
+
+	simple := map[rune]rune{
+		'b': '\b',
+		'f': '\f',
+		'n': '\n',
+		'r': '\r',
+		't': '\t',
+		'v': '\v',
+	}
+
+	if replacement, ok := simple[r]; ok {
+		b.WriteRune(replacement)
+		return nil
+	}
+
+	switch r {
+	case '0':
+		if next, _ := s.peekRune(s.pos); next >= '0' && next <= '9' {
+			return s.syntaxError(escapeStart, "octal escape sequences are not allowed")
+		}
+		b.WriteByte(0)
+	case 'x':
+		code, err := s.scanHex(escapeStart, 2)
+		if err != nil {
+			return err
+		}
+		b.WriteRune(rune(code))
+	case 'u':
+		code, err := s.scanHex(escapeStart, 4)
+		if err != nil {
+			return err
+		}
+		r1 := rune(code)
+		if utf16.IsSurrogate(r1) && s.pos+1 < len(s.data) && s.data[s.pos] == '\\' && s.data[s.pos+1] == 'u' {
+			save := s.pos
+			s.pos += 2
+			code2, err := s.scanHex(save, 4)
+			if err != nil {
+				return err
+			}
+			if decoded := utf16.DecodeRune(r1, rune(code2)); decoded != unicode.ReplacementChar {
+				b.WriteRune(decoded)
+				return nil
+			}
+			s.pos = save
+		}
+		b.WriteRune(r1)
+	case '\r':
+		// a line continuation, which may be \r\n
+		if s.pos < len(s.data) && s.data[s.pos] == '\n' {
+			s.pos++
+		}
+	case '\n', '\u2028', '\u2029':
+		// a line continuation contributes nothing to the string
+	default:
+		b.WriteRune(r)
+	}
+
+	return nil
+}
+
+func (s *json5Scanner) scanHex(escapeStart, digits int) (uint64, error) {
+	if s.pos+digits > len(s.data) {
+		return 0, s.syntaxError(escapeStart, "truncated escape sequence")
+	}
+
+	code, err := strconv.ParseUint(string(s.data[s.pos:s.pos+digits]), 16, 32)
+
+	if err != nil {
+		return 0, s.syntaxError(escapeStart, "invalid hexadecimal escape sequence")
+	}
+
+	s.pos += digits
+	return code, nil
+}
+
+func (s *json5Scanner) scanNumber() (json5Token, error) {
+	start := s.pos
+
+	if c := s.data[s.pos]; c == '+' || c == '-' {
+		s.pos++
+	}
+
+	rest := string(s.data[s.pos:])
+
+	for _, word := range []string{"Infinity", "NaN"} {
+		if strings.HasPrefix(rest, word) {
+			s.pos += len(word)
+			return s.finishNumber(start)
+		}
+	}
+
+	if strings.HasPrefix(rest, "0x") || strings.HasPrefix(rest, "0X") {
+		s.pos += 2
+		digitsStart := s.pos
+		for s.pos < len(s.data) && isHexDigit(s.data[s.pos]) {
+			s.pos++
+		}
+		if s.pos == digitsStart {
+			return json5Token{}, s.syntaxError(start, "invalid hexadecimal number")
+		}
+		return s.finishNumber(start)
+	}
+
+	intStart := s.pos
+	digits := s.skipDigits()
+
+	if digits > 1 && s.data[intStart] == '0' {
+		return json5Token{}, s.syntaxError(start, "leading zeros are not allowed")
+	}
+
+	if s.pos < len(s.data) && s.data[s.pos] == '.' {
+		s.pos++
+		digits += s.skipDigits()
+	}
+
+	if digits == 0 {
+		return json5Token{}, s.syntaxError(start, "invalid number")
+	}
+
+	if s.pos < len(s.data) && (s.data[s.pos] == 'e' || s.data[s.pos] == 'E') {
+		s.pos++
+		if s.pos < len(s.data) && (s.data[s.pos] == '+' || s.data[s.pos] == '-') {
+			s.pos++
+		}
+		if s.skipDigits() == 0 {
+			return json5Token{}, s.syntaxError(start, "invalid number exponent")
+		}
+	}
+
+	return s.finishNumber(start)
+}
+
+// finishNumber makes sure that a number is not immediately followed by an identifier character, e.g. 12abc
+func (s *json5Scanner) finishNumber(start int) (json5Token, error) {
+	if r, _ := s.peekRune(s.pos); s.pos < len(s.data) && isJSON5IdentPart(r) {
+		return json5Token{}, s.syntaxError(start, "invalid number")
+	}
+
+	return json5Token{kind: json5Number, start: start, end: s.pos, text: string(s.data[start:s.pos])}, nil
+}
+
+func (s *json5Scanner) skipDigits() int {
+	count := 0
+	for s.pos < len(s.data) && s.data[s.pos] >= '0' && s.data[s.pos] <= '9' {
+		s.pos++
+		count++
+	}
+	return count
+}
+
+func (s *json5Scanner) scanIdent() (json5Token, error) {
+	start := s.pos
+
+	for s.pos < len(s.data) {
+		r, size := s.peekRune(s.pos)
+		if !isJSON5IdentPart(r) {
+			break
+		}
+		s.pos += size
+	}
+
+	return json5Token{kind: json5Ident, start: start, end: s.pos, text: string(s.data[start:s.pos])}, nil
+}
+
+// json5Parser builds a Value from the tokens produced by a json5Scanner
+type json5Parser struct {
+	scanner json5Scanner
+	tok     json5Token
+}
+
+func (p *json5Parser) advance() error {
+	tok, err := p.scanner.next()
+
+	if err != nil {
+		return err
+	}
+
+	p.tok = tok
+	return nil
+}
+
+func (p *json5Parser) unexpected() error {
+	return p.scanner.syntaxError(p.tok.start, "unexpected %s", json5TokenNames[p.tok.kind])
+}
+
+func (p *json5Parser) parseValue() (Value, error) {
+	tok := p.tok
+
+	switch tok.kind {
+	case json5LeftBrace:
+		return p.parseObject()
+	case json5LeftBracket:
+		return p.parseArray()
+	case json5String:
+		return NewStringValue(tok.text), p.advance()
+	case json5Number:
+		v, err := json5NumberValue(tok.text)
+		if err != nil {
+			return Value{}, p.scanner.syntaxError(tok.start, "%s", err.Error())
+		}
+		return v, p.advance()
+	case json5Ident:
+		switch tok.text {
+		case "null":
+			return Value{}, p.advance()
+		case "true":
+			return NewBoolValue(true), p.advance()
+		case "false":
+			return NewBoolValue(false), p.advance()
+		case "Infinity", "NaN":
+			v, _ := json5NumberValue(tok.text)
+			return v, p.advance()
+		}
+		return Value{}, p.scanner.syntaxError(tok.start, "unexpected identifier %q", tok.text)
+	}
+
+	return Value{}, p.unexpected()
+}
+
+func (p *json5Parser) parseObject() (Value, error) {
+	o := NewObject(3)
+
+	if err := p.advance(); err != nil {
+		return Value{}, err
+	}
+
+	for p.tok.kind != json5RightBrace {
+		if p.tok.kind != json5String && p.tok.kind != json5Ident {
+			return Value{}, p.unexpected()
+		}
+
+		key := p.tok.text
+
+		if err := p.advance(); err != nil {
+			return Value{}, err
+		}
+
+		if p.tok.kind != json5Colon {
+			return Value{}, p.unexpected()
+		}
+
+		if err := p.advance(); err != nil {
+			return Value{}, err
+		}
+
+		member, err := p.parseValue()
+
+		if err != nil {
+			return Value{}, err
+		}
+
+		o[key] = member
+
+		if p.tok.kind == json5Comma {
+			if err := p.advance(); err != nil {
+				return Value{}, err
+			}
+		} else if p.tok.kind != json5RightBrace {
+			return Value{}, p.unexpected()
+		}
+	}
+
+	return NewObjectValue(o), p.advance()
+}
+
+func (p *json5Parser) parseArray() (Value, error) {
+	a := NewArray()
+
+	if err := p.advance(); err != nil {
+		return Value{}, err
+	}
+
+	for p.tok.kind != json5RightBracket {
+		element, err := p.parseValue()
+
+		if err != nil {
+			return Value{}, err
+		}
+
+		a = append(a, element)
+
+		if p.tok.kind == json5Comma {
+			if err := p.advance(); err != nil {
+				return Value{}, err
+			}
+		} else if p.tok.kind != json5RightBracket {
+			return Value{}, p.unexpected()
+		}
+	}
+
+	return NewArrayValue(a), p.advance()
+}
+
+// json5NumberValue converts a JSON5 numeric literal to an Int or Float Value using the same rules as UnmarshalJSON
+func json5NumberValue(text string) (Value, error) {
+	unsigned := strings.TrimLeft(text, "+-")
+	negative := strings.HasPrefix(text, "-")
+
+	switch {
+	case unsigned == "Infinity":
+		if negative {
+			return NewFloatValue(math.Inf(-1)), nil
+		}
+		return NewFloatValue(math.Inf(1)), nil
+	case unsigned == "NaN":
+		return NewFloatValue(math.NaN()), nil
+	case strings.HasPrefix(unsigned, "0x") || strings.HasPrefix(unsigned, "0X"):
+		i, err := strconv.ParseInt(unsigned[2:], 16, 64)
+		if err != nil {
+			return Value{}, fmt.Errorf("hexadecimal number %s is out of range", text)
+		}
+		if negative {
+			i = -i
+		}
+		return NewIntValue(int(i)), nil
+	}
+
+	if negative {
+		unsigned = "-" + unsigned
+	}
+
+	pt := Parse(unsigned)
+
+	if i, ok := pt.Integer(); ok {
+		return NewIntValue(i), nil
+	} else if f, ok := pt.Float(); ok {
+		return NewFloatValue(f), nil
+	}
+
+	return Value{}, fmt.Errorf("invalid number %s", text)
+}
+
+func isJSON5Space(r rune) bool {
+	switch r {
+	case '\t', '\n', '\v', '\f', '\r', ' ', '\u00A0', '\u2028', '\u2029', '\uFEFF':
+		return true
+	}
+
+	return unicode.Is(unicode.Zs, r)
+}
+
+func isJSON5LineTerminator(r rune) bool {
+	return r == '\n' || r == '\r' || r == '\u2028' || r == '\u2029'
+}
+
+func isJSON5IdentStart(r rune) bool {
+	return r == '$' || r == '_' || unicode.IsLetter(r)
+}
+
+func isJSON5IdentPart(r rune) bool {
+	return isJSON5IdentStart(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r) ||
+		unicode.Is(unicode.Pc, r)
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
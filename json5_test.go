@@ -0,0 +1,163 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/webern/tcore"
+)
+
+func TestParseJSON5(t *testing.T) {
+	type TestCase struct {
+		Input           string
+		IsErrorExpected bool
+		Expected        Value
+	}
+
+	testCases := []TestCase{
+		{
+			Input: `// a comment
+			{
+				/* block
+				   comment */
+				unquoted: 'single',
+				"quoted": "double", // trailing comment
+				trailing: [1, 2, 3,],
+			}`,
+			Expected: NewObjectValue(Object{
+				"unquoted": NewStringValue("single"),
+				"quoted":   NewStringValue("double"),
+				"trailing": NewArrayValue(Array{NewIntValue(1), NewIntValue(2), NewIntValue(3)}),
+			}),
+		},
+		{
+			Input: `[0x1F, -0x10, +5, .5, 5., 1e2, 1.5E-1]`,
+			Expected: NewArrayValue(Array{
+				NewIntValue(31), NewIntValue(-16), NewIntValue(5), NewFloatValue(0.5), NewIntValue(5), NewIntValue(100),
+				NewFloatValue(0.15),
+			}),
+		},
+		{
+			Input: `'it\'s a \
+continued \x41B string'`,
+			Expected: NewStringValue("it's a continued AB string"),
+		},
+		{
+			Input:    `"\uD83D\uDE00"`,
+			Expected: NewStringValue("\U0001F600"),
+		},
+		{
+			Input:    `{ $key_1: null, yes: true, no: false }`,
+			Expected: NewObjectValue(Object{"$key_1": NewValue(), "yes": NewBoolValue(true), "no": NewBoolValue(false)}),
+		},
+		{
+			Input:           `{ "a": 1 `,
+			IsErrorExpected: true,
+		},
+		{
+			Input:           `[1,,2]`,
+			IsErrorExpected: true,
+		},
+		{
+			Input:           `/* unterminated`,
+			IsErrorExpected: true,
+		},
+		{
+			Input:           `{ a: undefined }`,
+			IsErrorExpected: true,
+		},
+		{
+			Input:           `12abc`,
+			IsErrorExpected: true,
+		},
+		{
+			Input:           `[01]`,
+			IsErrorExpected: true,
+		},
+		{
+			Input:           `-00.5`,
+			IsErrorExpected: true,
+		},
+		{
+			Input:    `[0, -0.5, 0e1]`,
+			Expected: NewArrayValue(Array{NewIntValue(0), NewFloatValue(-0.5), NewIntValue(0)}),
+		},
+		{
+			Input: `"line
+break"`,
+			IsErrorExpected: true,
+		},
+	}
+
+	for tcix, tc := range testCases {
+		stm := fmt.Sprintf("test case %d: actual, err := ParseJSON5([]byte(tc.Input))", tcix)
+		actual, err := ParseJSON5([]byte(tc.Input))
+
+		if tc.IsErrorExpected {
+			if err == nil {
+				t.Errorf("an error was expected but none was received for the statement '%s'", stm)
+			} else if _, ok := err.(*JSON5SyntaxError); !ok {
+				t.Errorf("%s - expected a *JSON5SyntaxError but got %T", stm, err)
+			}
+			continue
+		}
+
+		if msg, ok := tcore.TErr(stm, err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		stm = fmt.Sprintf("test case %d: actual.Equals(tc.Expected)", tcix)
+		if msg, ok := tcore.TAssertBool(stm, actual.Equals(tc.Expected), true); !ok {
+			t.Error(msg + fmt.Sprintf(" - '%v' != '%v'", actual, tc.Expected))
+		}
+	}
+}
+
+func TestParseJSON5_InfinityAndNaN(t *testing.T) {
+	actual, err := ParseJSON5([]byte(`[Infinity, -Infinity, +Infinity, NaN]`))
+
+	if msg, ok := tcore.TErr("ParseJSON5", err); !ok {
+		t.Fatal(msg)
+	}
+
+	a := actual.Array()
+
+	if msg, ok := tcore.TAssertInt("len(a)", len(a), 4); !ok {
+		t.Fatal(msg)
+	}
+
+	if !math.IsInf(a[0].Float(), 1) || !math.IsInf(a[1].Float(), -1) || !math.IsInf(a[2].Float(), 1) {
+		t.Errorf("expected infinities but got %v, %v, %v", a[0].Float(), a[1].Float(), a[2].Float())
+	}
+
+	if !math.IsNaN(a[3].Float()) {
+		t.Errorf("expected NaN but got %v", a[3].Float())
+	}
+}
+
+func TestParseJSON5_MatchesJSON(t *testing.T) {
+	input := `{"a": [1, 2.5, "x", null, true, {"b": 1.0000000000000000001}], "c": {}}`
+
+	var fromJSON Value
+	err := json.Unmarshal([]byte(input), &fromJSON)
+
+	if msg, ok := tcore.TErr("json.Unmarshal", err); !ok {
+		t.Fatal(msg)
+	}
+
+	var fromJSON5 Value
+	err = fromJSON5.UnmarshalJSON5([]byte(input))
+
+	if msg, ok := tcore.TErr("fromJSON5.UnmarshalJSON5", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("fromJSON5.Equals(fromJSON)", fromJSON5.Equals(fromJSON), true); !ok {
+		t.Error(msg)
+	}
+}
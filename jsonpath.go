@@ -0,0 +1,1144 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// JSONPath is a compiled RFC 9535 JSONPath query. It supports name, index, wildcard, slice and filter selectors,
+// unions of selectors, descendant segments, and the length, count, match, search and value functions. Filters compare
+// Int and Float numerically and compare Arrays and Objects by deep equality.
+type JSONPath struct {
+	expr  string
+	query *jpQuery
+}
+
+// JSONPathMatch is a Value selected by a JSONPath query along with its normalized path, e.g. $['store']['book'][0]
+type JSONPathMatch struct {
+	Value Value
+	Path  string
+}
+
+// CompileJSONPath parses a JSONPath query such as $.store.book[?@.price < 10].title
+func CompileJSONPath(expr string) (*JSONPath, error) {
+	p := jpParser{expr: expr}
+	p.skipBlank()
+
+	if !p.consume("$") {
+		return nil, p.errorf("a query must begin with $")
+	}
+
+	query, err := p.parseSegments(false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipBlank()
+
+	if !p.done() {
+		return nil, p.errorf("unexpected %q", p.rest())
+	}
+
+	return &JSONPath{expr: expr, query: query}, nil
+}
+
+// QueryJSONPath compiles expr and evaluates it against v
+func QueryJSONPath(expr string, v Value) ([]JSONPathMatch, error) {
+	jp, err := CompileJSONPath(expr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return jp.Query(v), nil
+}
+
+// String returns the query as it was written
+func (jp *JSONPath) String() string {
+	return jp.expr
+}
+
+// Query evaluates the JSONPath against v and returns the selected Values in document order. Object members are
+// visited in key order.
+func (jp *JSONPath) Query(v Value) []JSONPathMatch {
+	nodes := jp.query.eval(v, jpNode{value: v, path: "$"})
+	matches := make([]JSONPathMatch, len(nodes))
+
+	for i, node := range nodes {
+		matches[i] = JSONPathMatch{Value: node.value, Path: node.path}
+	}
+
+	return matches
+}
+
+// Private
+
+type jpNode struct {
+	value Value
+	path  string
+}
+
+type jpQuery struct {
+	relative bool
+	segments []jpSegment
+}
+
+type jpSegment struct {
+	descendant bool
+	selectors  []jpSelector
+}
+
+type jpSelectorKind int
+
+const (
+	jpName jpSelectorKind = iota
+	jpWildcard
+	jpIndex
+	jpSlice
+	jpFilter
+)
+
+type jpSelector struct {
+	kind   jpSelectorKind
+	name   string
+	index  int
+	start  *int
+	end    *int
+	step   *int
+	filter jpLogical
+}
+
+// isSingular reports whether the query can select at most one node
+func (q *jpQuery) isSingular() bool {
+	for _, segment := range q.segments {
+		if segment.descendant || len(segment.selectors) != 1 {
+			return false
+		}
+		if kind := segment.selectors[0].kind; kind != jpName && kind != jpIndex {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (q *jpQuery) eval(root Value, start jpNode) []jpNode {
+	nodes := []jpNode{start}
+
+	for _, segment := range q.segments {
+		var next []jpNode
+		for _, node := range nodes {
+			if segment.descendant {
+				for _, descendant := range jpDescendants(node) {
+					next = segment.apply(root, descendant, next)
+				}
+			} else {
+				next = segment.apply(root, node, next)
+			}
+		}
+		nodes = next
+	}
+
+	return nodes
+}
+
+func (s jpSegment) apply(root Value, node jpNode, out []jpNode) []jpNode {
+	for _, selector := range s.selectors {
+		out = selector.apply(root, node, out)
+	}
+
+	return out
+}
+
+// jpDescendants returns node followed by all of its descendants in document order
+func jpDescendants(node jpNode) []jpNode {
+	out := []jpNode{node}
+
+	for _, c := range jpChildren(node) {
+		out = append(out, jpDescendants(c)...)
+	}
+
+	return out
+}
+
+func jpChildren(node jpNode) []jpNode {
+	var out []jpNode
+
+	switch node.value.Type() {
+	case ObjectType:
+		o := node.value.Object()
+		for _, key := range sortedKeys(o) {
+			out = append(out, jpNode{value: o[key], path: node.path + jpNormalizedName(key)})
+		}
+	case ArrayType:
+		for i, element := range node.value.Array() {
+			out = append(out, jpNode{value: element, path: fmt.Sprintf("%s[%d]", node.path, i)})
+		}
+	}
+
+	return out
+}
+
+func (s jpSelector) apply(root Value, node jpNode, out []jpNode) []jpNode {
+	switch s.kind {
+	case jpName:
+		if node.value.Type() == ObjectType {
+			if c, ok := node.value.Object()[s.name]; ok {
+				out = append(out, jpNode{value: c, path: node.path + jpNormalizedName(s.name)})
+			}
+		}
+	case jpWildcard:
+		out = append(out, jpChildren(node)...)
+	case jpIndex:
+		if node.value.Type() == ArrayType {
+			a := node.value.Array()
+			i := s.index
+			if i < 0 {
+				i += len(a)
+			}
+			if i >= 0 && i < len(a) {
+				out = append(out, jpNode{value: a[i], path: fmt.Sprintf("%s[%d]", node.path, i)})
+			}
+		}
+	case jpSlice:
+		if node.value.Type() == ArrayType {
+			a := node.value.Array()
+			for _, i := range s.sliceIndices(len(a)) {
+				out = append(out, jpNode{value: a[i], path: fmt.Sprintf("%s[%d]", node.path, i)})
+			}
+		}
+	case jpFilter:
+		for _, c := range jpChildren(node) {
+			if s.filter.test(root, c.value) {
+				out = append(out, c)
+			}
+		}
+	}
+
+	return out
+}
+
+// sliceIndices implements the array slice selector of RFC 9535 section 2.3.4.2
+func (s jpSelector) sliceIndices(length int) []int {
+	step := 1
+
+	if s.step != nil {
+		step = *s.step
+	}
+
+	if step == 0 {
+		return nil
+	}
+
+	normalize := func(i int) int {
+		if i < 0 {
+			return i + length
+		}
+		return i
+	}
+
+	clamp := func(i, lower, upper int) int {
+		if i < lower {
+			return lower
+		} else if i > upper {
+			return upper
+		}
+		return i
+	}
+
+	var indices []int
+
+	if step > 0 {
+		start, end := 0, length
+		if s.start != nil {
+			start = *s.start
+		}
+		if s.end != nil {
+			end = *s.end
+		}
+		lower, upper := clamp(normalize(start), 0, length), clamp(normalize(end), 0, length)
+		for i := lower; i < upper; i += step {
+			indices = append(indices, i)
+		}
+		return indices
+	}
+
+	start, end := length-1, -length-1
+	if s.start != nil {
+		start = *s.start
+	}
+	if s.end != nil {
+		end = *s.end
+	}
+	upper, lower := clamp(normalize(start), -1, length-1), clamp(normalize(end), -1, length-1)
+	for i := upper; lower < i; i += step {
+		indices = append(indices, i)
+	}
+
+	return indices
+}
+
+// jpNormalizedName renders a member name as a normalized path segment, e.g. ['a']
+func jpNormalizedName(name string) string {
+	b := strings.Builder{}
+	b.WriteString("['")
+
+	for _, r := range name {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\'':
+			b.WriteString(`\'`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+
+	b.WriteString("']")
+	return b.String()
+}
+
+func sortedKeys(o Object) []string {
+	keys := make([]string, 0, len(o))
+
+	for key := range o {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// filter expressions
+
+// jpLogical is a filter expression that produces true or false
+type jpLogical interface {
+	test(root, current Value) bool
+}
+
+// jpOperand is a filter expression that produces a single value or nothing, e.g. a literal or a singular query
+type jpOperand interface {
+	value(root, current Value) (Value, bool)
+}
+
+type jpOr struct{ left, right jpLogical }
+type jpAnd struct{ left, right jpLogical }
+type jpNot struct{ operand jpLogical }
+
+func (e jpOr) test(root, current Value) bool {
+	return e.left.test(root, current) || e.right.test(root, current)
+}
+func (e jpAnd) test(root, current Value) bool {
+	return e.left.test(root, current) && e.right.test(root, current)
+}
+func (e jpNot) test(root, current Value) bool { return !e.operand.test(root, current) }
+
+// jpExists is a query used as a test, which is true when the query selects at least one node
+type jpExists struct{ query *jpQuery }
+
+func (e jpExists) test(root, current Value) bool {
+	return len(e.query.evalFrom(root, current)) > 0
+}
+
+func (q *jpQuery) evalFrom(root, current Value) []jpNode {
+	if q.relative {
+		return q.eval(root, jpNode{value: current, path: "@"})
+	}
+
+	return q.eval(root, jpNode{value: root, path: "$"})
+}
+
+// jpSingular is a singular query used as an operand
+type jpSingular struct{ query *jpQuery }
+
+func (e jpSingular) value(root, current Value) (Value, bool) {
+	nodes := e.query.evalFrom(root, current)
+
+	if len(nodes) != 1 {
+		return Value{}, false
+	}
+
+	return nodes[0].value, true
+}
+
+type jpLiteral struct{ literal Value }
+
+func (e jpLiteral) value(root, current Value) (Value, bool) {
+	return e.literal, true
+}
+
+type jpComparison struct {
+	op          string
+	left, right jpOperand
+}
+
+func (e jpComparison) test(root, current Value) bool {
+	l, lok := e.left.value(root, current)
+	r, rok := e.right.value(root, current)
+
+	switch e.op {
+	case "==":
+		return jpEqual(l, lok, r, rok)
+	case "!=":
+		return !jpEqual(l, lok, r, rok)
+	case "<":
+		return lok && rok && jpLess(l, r)
+	case ">":
+		return lok && rok && jpLess(r, l)
+	case "<=":
+		return (lok && rok && jpLess(l, r)) || jpEqual(l, lok, r, rok)
+	case ">=":
+		return (lok && rok && jpLess(r, l)) || jpEqual(l, lok, r, rok)
+	}
+
+	return false
+}
+
+func jpIsNumber(v Value) bool {
+	return v.IsInt() || v.IsFloat()
+}
+
+func jpNumber(v Value) float64 {
+	if v.IsInt() {
+		return float64(v.Int())
+	}
+
+	return v.Float()
+}
+
+// jpEqual compares two operands, either of which may be nothing, using the rules of RFC 9535 section 2.3.5.2.2
+func jpEqual(l Value, lok bool, r Value, rok bool) bool {
+	if !lok || !rok {
+		return lok == rok
+	}
+
+	if jpIsNumber(l) && jpIsNumber(r) {
+		if l.IsInt() && r.IsInt() {
+			return l.Int() == r.Int()
+		}
+		return jpNumber(l) == jpNumber(r)
+	}
+
+	if l.Type() != r.Type() {
+		return false
+	}
+
+	switch l.Type() {
+	case ArrayType:
+		la, ra := l.Array(), r.Array()
+		if len(la) != len(ra) {
+			return false
+		}
+		for i := range la {
+			if !jpEqual(la[i], true, ra[i], true) {
+				return false
+			}
+		}
+		return true
+	case ObjectType:
+		lo, ro := l.Object(), r.Object()
+		if len(lo) != len(ro) {
+			return false
+		}
+		for key, lv := range lo {
+			rv, ok := ro[key]
+			if !ok || !jpEqual(lv, true, rv, true) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return l.Equals(r)
+}
+
+func jpLess(l, r Value) bool {
+	if jpIsNumber(l) && jpIsNumber(r) {
+		if l.IsInt() && r.IsInt() {
+			return l.Int() < r.Int()
+		}
+		return jpNumber(l) < jpNumber(r)
+	}
+
+	if l.IsString() && r.IsString() {
+		return l.String() < r.String()
+	}
+
+	return false
+}
+
+// functions
+
+type jpFunctionType int
+
+const (
+	jpValueType jpFunctionType = iota
+	jpLogicalType
+	jpNodesType
+)
+
+type jpFunction struct {
+	name  string
+	args  []interface{}
+	regex *regexp.Regexp // precompiled when the pattern of match or search is a literal
+}
+
+var jpFunctionSignatures = map[string]struct {
+	result jpFunctionType
+	params []jpFunctionType
+}{
+	"length": {result: jpValueType, params: []jpFunctionType{jpValueType}},
+	"count":  {result: jpValueType, params: []jpFunctionType{jpNodesType}},
+	"match":  {result: jpLogicalType, params: []jpFunctionType{jpValueType, jpValueType}},
+	"search": {result: jpLogicalType, params: []jpFunctionType{jpValueType, jpValueType}},
+	"value":  {result: jpValueType, params: []jpFunctionType{jpNodesType}},
+}
+
+func (f *jpFunction) argValue(i int, root, current Value) (Value, bool) {
+	return f.args[i].(jpOperand).value(root, current)
+}
+
+func (f *jpFunction) value(root, current Value) (Value, bool) {
+	switch f.name {
+	case "length":
+		v, ok := f.argValue(0, root, current)
+		if !ok {
+			return Value{}, false
+		}
+		switch v.Type() {
+		case String:
+			return NewIntValue(utf8.RuneCountInString(v.String())), true
+		case ArrayType:
+			return NewIntValue(len(v.Array())), true
+		case ObjectType:
+			return NewIntValue(len(v.Object())), true
+		}
+		return Value{}, false
+	case "count":
+		return NewIntValue(len(f.args[0].(*jpQuery).evalFrom(root, current))), true
+	case "value":
+		nodes := f.args[0].(*jpQuery).evalFrom(root, current)
+		if len(nodes) != 1 {
+			return Value{}, false
+		}
+		return nodes[0].value, true
+	}
+
+	return Value{}, false
+}
+
+func (f *jpFunction) test(root, current Value) bool {
+	s, ok := f.argValue(0, root, current)
+
+	if !ok || !s.IsString() {
+		return false
+	}
+
+	re := f.regex
+
+	if re == nil {
+		pattern, ok := f.argValue(1, root, current)
+		if !ok || !pattern.IsString() {
+			return false
+		}
+		var err error
+		if re, err = jpCompileRegex(f.name, pattern.String()); err != nil {
+			return false
+		}
+	}
+
+	return re.MatchString(s.String())
+}
+
+func jpCompileRegex(function, pattern string) (*regexp.Regexp, error) {
+	if function == "match" {
+		return regexp.Compile(`^(?:` + pattern + `)$`)
+	}
+
+	return regexp.Compile(pattern)
+}
+
+// parsing
+
+type jpParser struct {
+	expr string
+	pos  int
+}
+
+func (p *jpParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("jsonpath: %s at offset %d of %q", fmt.Sprintf(format, args...), p.pos, p.expr)
+}
+
+func (p *jpParser) done() bool {
+	return p.pos >= len(p.expr)
+}
+
+func (p *jpParser) rest() string {
+	return p.expr[p.pos:]
+}
+
+func (p *jpParser) peek() byte {
+	if p.done() {
+		return 0
+	}
+
+	return p.expr[p.pos]
+}
+
+func (p *jpParser) consume(s string) bool {
+	if strings.HasPrefix(p.rest(), s) {
+		p.pos += len(s)
+		return true
+	}
+
+	return false
+}
+
+func (p *jpParser) skipBlank() {
+	for !p.done() && strings.IndexByte(" \t\n\r", p.peek()) >= 0 {
+		p.pos++
+	}
+}
+
+// parseSegments parses the segments that follow $ or @
+func (p *jpParser) parseSegments(relative bool) (*jpQuery, error) {
+	query := &jpQuery{relative: relative}
+
+	for {
+		save := p.pos
+		p.skipBlank()
+
+		var segment jpSegment
+		var err error
+
+		switch {
+		case p.consume(".."):
+			segment.descendant = true
+			if p.peek() == '[' {
+				segment.selectors, err = p.parseBracketed()
+			} else {
+				segment.selectors, err = p.parseShorthand()
+			}
+		case p.consume("."):
+			segment.selectors, err = p.parseShorthand()
+		case p.peek() == '[':
+			segment.selectors, err = p.parseBracketed()
+		default:
+			p.pos = save
+			return query, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		query.segments = append(query.segments, segment)
+	}
+}
+
+func (p *jpParser) parseShorthand() ([]jpSelector, error) {
+	if p.consume("*") {
+		return []jpSelector{{kind: jpWildcard}}, nil
+	}
+
+	start := p.pos
+
+	for !p.done() {
+		r, size := utf8.DecodeRuneInString(p.rest())
+		isFirst := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r >= 0x80
+		if !isFirst && !(p.pos > start && r >= '0' && r <= '9') {
+			break
+		}
+		p.pos += size
+	}
+
+	if p.pos == start {
+		return nil, p.errorf("expected a member name or *")
+	}
+
+	return []jpSelector{{kind: jpName, name: p.expr[start:p.pos]}}, nil
+}
+
+func (p *jpParser) parseBracketed() ([]jpSelector, error) {
+	p.pos++ // [
+	var selectors []jpSelector
+
+	for {
+		p.skipBlank()
+		selector, err := p.parseSelector()
+
+		if err != nil {
+			return nil, err
+		}
+
+		selectors = append(selectors, selector)
+		p.skipBlank()
+
+		if p.consume("]") {
+			return selectors, nil
+		} else if !p.consume(",") {
+			return nil, p.errorf("expected , or ]")
+		}
+	}
+}
+
+func (p *jpParser) parseSelector() (jpSelector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		return jpSelector{kind: jpName, name: name}, err
+	case c == '*':
+		p.pos++
+		return jpSelector{kind: jpWildcard}, nil
+	case c == '?':
+		p.pos++
+		p.skipBlank()
+		filter, err := p.parseOr()
+		return jpSelector{kind: jpFilter, filter: filter}, err
+	}
+
+	// an index or a slice, where each part of a slice is optional
+	var parts [3]*int
+	part := 0
+
+	for {
+		p.skipBlank()
+
+		if c := p.peek(); c == '-' || (c >= '0' && c <= '9') {
+			i, err := p.parseInt()
+			if err != nil {
+				return jpSelector{}, err
+			}
+			parts[part] = &i
+			p.skipBlank()
+		}
+
+		if part < 2 && p.consume(":") {
+			part++
+			continue
+		}
+
+		break
+	}
+
+	if part == 0 {
+		if parts[0] == nil {
+			return jpSelector{}, p.errorf("expected a selector")
+		}
+		return jpSelector{kind: jpIndex, index: *parts[0]}, nil
+	}
+
+	return jpSelector{kind: jpSlice, start: parts[0], end: parts[1], step: parts[2]}, nil
+}
+
+// parseInt parses an integer in the range of I-JSON, without leading zeros
+func (p *jpParser) parseInt() (int, error) {
+	start := p.pos
+	p.consume("-")
+	digits := p.pos
+
+	for !p.done() && p.peek() >= '0' && p.peek() <= '9' {
+		p.pos++
+	}
+
+	text := p.expr[start:p.pos]
+
+	if p.pos == digits || (p.expr[digits] == '0' && p.pos-digits > 1) || text == "-0" {
+		return 0, p.errorf("invalid integer %q", text)
+	}
+
+	i, err := strconv.ParseInt(text, 10, 64)
+
+	if err != nil || i > 1<<53-1 || i < -(1<<53-1) {
+		return 0, p.errorf("integer %q is out of range", text)
+	}
+
+	return int(i), nil
+}
+
+func (p *jpParser) parseString() (string, error) {
+	quote := p.peek()
+	p.pos++
+	b := strings.Builder{}
+
+	for {
+		if p.done() {
+			return "", p.errorf("unterminated string")
+		}
+
+		r, size := utf8.DecodeRuneInString(p.rest())
+
+		switch {
+		case r == rune(quote):
+			p.pos += size
+			return b.String(), nil
+		case r < 0x20:
+			return "", p.errorf("control characters must be escaped in strings")
+		case r != '\\':
+			b.WriteRune(r)
+			p.pos += size
+			continue
+		}
+
+		p.pos++
+		escaped := p.peek()
+		p.pos++
+
+		switch escaped {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '/', '\\':
+			b.WriteByte(escaped)
+		case 'u':
+			r, err := p.parseUnicodeEscape()
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(r)
+		default:
+			if escaped != quote {
+				return "", p.errorf("invalid escape sequence")
+			}
+			b.WriteByte(escaped)
+		}
+	}
+}
+
+func (p *jpParser) parseUnicodeEscape() (rune, error) {
+	hex := func() (rune, error) {
+		if len(p.rest()) < 4 {
+			return 0, p.errorf("truncated unicode escape")
+		}
+		u, err := strconv.ParseUint(p.expr[p.pos:p.pos+4], 16, 32)
+		if err != nil {
+			return 0, p.errorf("invalid unicode escape")
+		}
+		p.pos += 4
+		return rune(u), nil
+	}
+
+	r, err := hex()
+
+	if err != nil || !utf16.IsSurrogate(r) {
+		return r, err
+	}
+
+	if !p.consume(`\u`) {
+		return 0, p.errorf("unpaired surrogate in unicode escape")
+	}
+
+	r2, err := hex()
+
+	if err != nil {
+		return 0, err
+	}
+
+	decoded := utf16.DecodeRune(r, r2)
+
+	if decoded == utf8.RuneError {
+		return 0, p.errorf("invalid surrogate pair in unicode escape")
+	}
+
+	return decoded, nil
+}
+
+func (p *jpParser) parseOr() (jpLogical, error) {
+	left, err := p.parseAnd()
+
+	for err == nil {
+		p.skipBlank()
+		if !p.consume("||") {
+			return left, nil
+		}
+		p.skipBlank()
+		var right jpLogical
+		if right, err = p.parseAnd(); err == nil {
+			left = jpOr{left: left, right: right}
+		}
+	}
+
+	return nil, err
+}
+
+func (p *jpParser) parseAnd() (jpLogical, error) {
+	left, err := p.parseBasic()
+
+	for err == nil {
+		p.skipBlank()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		p.skipBlank()
+		var right jpLogical
+		if right, err = p.parseBasic(); err == nil {
+			left = jpAnd{left: left, right: right}
+		}
+	}
+
+	return nil, err
+}
+
+func (p *jpParser) parseBasic() (jpLogical, error) {
+	if p.consume("!") {
+		p.skipBlank()
+		operand, err := p.parseNegatable()
+		if err != nil {
+			return nil, err
+		}
+		return jpNot{operand: operand}, nil
+	}
+
+	if p.peek() == '(' {
+		return p.parseNegatable()
+	}
+
+	left, err := p.parseOperand()
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipBlank()
+
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !p.consume(op) {
+			continue
+		}
+		p.skipBlank()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		l, err := p.comparable(left)
+		if err != nil {
+			return nil, err
+		}
+		r, err := p.comparable(right)
+		if err != nil {
+			return nil, err
+		}
+		return jpComparison{op: op, left: l, right: r}, nil
+	}
+
+	return p.testable(left)
+}
+
+// parseNegatable parses what may follow !, which is a parenthesized expression, a query or a function
+func (p *jpParser) parseNegatable() (jpLogical, error) {
+	if p.consume("(") {
+		p.skipBlank()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipBlank()
+		if !p.consume(")") {
+			return nil, p.errorf("expected )")
+		}
+		return inner, nil
+	}
+
+	operand, err := p.parseOperand()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return p.testable(operand)
+}
+
+// parseOperand parses a literal, a query or a function call. The result is a Value, a *jpQuery or a *jpFunction.
+func (p *jpParser) parseOperand() (interface{}, error) {
+	c := p.peek()
+
+	switch {
+	case c == '@' || c == '$':
+		p.pos++
+		return p.parseSegments(c == '@')
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		return NewStringValue(s), err
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	}
+
+	for _, word := range []string{"true", "false", "null"} {
+		if strings.HasPrefix(p.rest(), word) && !p.isFunctionNameAt(p.pos+len(word)) {
+			p.pos += len(word)
+			switch word {
+			case "true":
+				return NewBoolValue(true), nil
+			case "false":
+				return NewBoolValue(false), nil
+			}
+			return Value{}, nil
+		}
+	}
+
+	return p.parseFunction()
+}
+
+func (p *jpParser) isFunctionNameAt(pos int) bool {
+	if pos >= len(p.expr) {
+		return false
+	}
+
+	c := p.expr[pos]
+	return c == '_' || c == '(' || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
+
+func (p *jpParser) parseNumber() (Value, error) {
+	start := p.pos
+	p.consume("-")
+
+	for !p.done() && strings.IndexByte("0123456789.eE+-", p.peek()) >= 0 {
+		p.pos++
+	}
+
+	text := p.expr[start:p.pos]
+	pt := Parse(text)
+
+	if _, err := strconv.ParseFloat(text, 64); err != nil || strings.HasPrefix(text, ".") {
+		return Value{}, p.errorf("invalid number %q", text)
+	}
+
+	if i, ok := pt.Integer(); ok && !strings.ContainsAny(text, ".eE") {
+		return NewIntValue(i), nil
+	}
+
+	f, _ := pt.Float()
+	return NewFloatValue(f), nil
+}
+
+func (p *jpParser) parseFunction() (*jpFunction, error) {
+	start := p.pos
+
+	for !p.done() && (p.peek() == '_' || (p.peek() >= 'a' && p.peek() <= 'z') || (p.peek() >= '0' && p.peek() <= '9')) {
+		p.pos++
+	}
+
+	name := p.expr[start:p.pos]
+	signature, ok := jpFunctionSignatures[name]
+
+	if !ok || !p.consume("(") {
+		p.pos = start
+		return nil, p.errorf("expected a literal, query or function")
+	}
+
+	f := &jpFunction{name: name}
+
+	for i, param := range signature.params {
+		p.skipBlank()
+
+		if i > 0 && !p.consume(",") {
+			return nil, p.errorf("%s() takes %d arguments", name, len(signature.params))
+		}
+
+		p.skipBlank()
+		arg, err := p.parseOperand()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if param == jpNodesType {
+			query, ok := arg.(*jpQuery)
+			if !ok {
+				return nil, p.errorf("the argument of %s() must be a query", name)
+			}
+			f.args = append(f.args, query)
+			continue
+		}
+
+		operand, err := p.comparable(arg)
+
+		if err != nil {
+			return nil, err
+		}
+
+		f.args = append(f.args, operand)
+	}
+
+	p.skipBlank()
+
+	if !p.consume(")") {
+		return nil, p.errorf("%s() takes %d arguments", name, len(signature.params))
+	}
+
+	if name == "match" || name == "search" {
+		if literal, ok := f.args[1].(jpLiteral); ok && literal.literal.IsString() {
+			re, err := jpCompileRegex(name, literal.literal.String())
+			if err != nil {
+				return nil, p.errorf("invalid regular expression: %s", err.Error())
+			}
+			f.regex = re
+		}
+	}
+
+	return f, nil
+}
+
+// comparable converts an operand to something that produces a single value, as comparisons require
+func (p *jpParser) comparable(operand interface{}) (jpOperand, error) {
+	switch o := operand.(type) {
+	case Value:
+		return jpLiteral{literal: o}, nil
+	case *jpQuery:
+		if !o.isSingular() {
+			return nil, p.errorf("only singular queries can be compared")
+		}
+		return jpSingular{query: o}, nil
+	case *jpFunction:
+		if jpFunctionSignatures[o.name].result != jpValueType {
+			return nil, p.errorf("the result of %s() cannot be compared", o.name)
+		}
+		return o, nil
+	}
+
+	return nil, p.errorf("unexpected operand")
+}
+
+// testable converts an operand to a test expression, which must be a query or a logical function
+func (p *jpParser) testable(operand interface{}) (jpLogical, error) {
+	switch o := operand.(type) {
+	case *jpQuery:
+		return jpExists{query: o}, nil
+	case *jpFunction:
+		if jpFunctionSignatures[o.name].result == jpLogicalType {
+			return o, nil
+		}
+		return nil, p.errorf("the result of %s() must be compared", o.name)
+	}
+
+	return nil, p.errorf("a literal must be compared")
+}
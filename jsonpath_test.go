@@ -0,0 +1,141 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/webern/tcore"
+)
+
+// jsonPathStore is the example document from section 1.5 of RFC 9535
+const jsonPathStore = `{ "store": {
+	"book": [
+		{ "category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95 },
+		{ "category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99 },
+		{ "category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3",
+		  "price": 8.99 },
+		{ "category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8",
+		  "price": 22.99 }
+	],
+	"bicycle": { "color": "red", "price": 399 }
+}}`
+
+func TestJSONPath_Query(t *testing.T) {
+	var store Value
+
+	if err := json.Unmarshal([]byte(jsonPathStore), &store); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Expr          string
+		ExpectedPaths []string
+	}{
+		{Expr: "$", ExpectedPaths: []string{"$"}},
+		{Expr: "$.store.book[*].author", ExpectedPaths: []string{
+			"$['store']['book'][0]['author']", "$['store']['book'][1]['author']",
+			"$['store']['book'][2]['author']", "$['store']['book'][3]['author']",
+		}},
+		{Expr: "$..author", ExpectedPaths: []string{
+			"$['store']['book'][0]['author']", "$['store']['book'][1]['author']",
+			"$['store']['book'][2]['author']", "$['store']['book'][3]['author']",
+		}},
+		{Expr: "$.store.*", ExpectedPaths: []string{"$['store']['bicycle']", "$['store']['book']"}},
+		{Expr: "$.store..price", ExpectedPaths: []string{
+			"$['store']['bicycle']['price']", "$['store']['book'][0]['price']", "$['store']['book'][1]['price']",
+			"$['store']['book'][2]['price']", "$['store']['book'][3]['price']",
+		}},
+		{Expr: "$..book[2]", ExpectedPaths: []string{"$['store']['book'][2]"}},
+		{Expr: "$..book[-1]", ExpectedPaths: []string{"$['store']['book'][3]"}},
+		{Expr: "$..book[0,1]", ExpectedPaths: []string{"$['store']['book'][0]", "$['store']['book'][1]"}},
+		{Expr: "$..book[:2]", ExpectedPaths: []string{"$['store']['book'][0]", "$['store']['book'][1]"}},
+		{Expr: "$..book[::-2]", ExpectedPaths: []string{"$['store']['book'][3]", "$['store']['book'][1]"}},
+		{Expr: "$..book[?@.isbn]", ExpectedPaths: []string{"$['store']['book'][2]", "$['store']['book'][3]"}},
+		{Expr: "$..book[?@.price<10]", ExpectedPaths: []string{"$['store']['book'][0]", "$['store']['book'][2]"}},
+		{
+			Expr:          "$..book[?@.price < 10 && @.category == 'fiction'].title",
+			ExpectedPaths: []string{"$['store']['book'][2]['title']"},
+		},
+		{Expr: "$..book[?!(@.price < 10 || @.isbn)]", ExpectedPaths: []string{"$['store']['book'][1]"}},
+		{Expr: "$..book[?@.price == $.store.bicycle.price]", ExpectedPaths: nil},
+		{Expr: `$..book[?match(@.author, "J.*")]`, ExpectedPaths: []string{"$['store']['book'][3]"}},
+		{
+			Expr:          `$..book[?search(@.title, "of")]`,
+			ExpectedPaths: []string{"$['store']['book'][0]", "$['store']['book'][1]", "$['store']['book'][3]"},
+		},
+		{Expr: `$.store[?length(@) == 2]`, ExpectedPaths: []string{"$['store']['bicycle']"}},
+		{Expr: `$[?count(@.*) == 2]`, ExpectedPaths: []string{"$['store']"}},
+		{Expr: `$.store.bicycle[?@ == 399]`, ExpectedPaths: []string{"$['store']['bicycle']['price']"}},
+		{Expr: `$.store.bicycle[?@ == 399.0]`, ExpectedPaths: []string{"$['store']['bicycle']['price']"}},
+		{Expr: `$["store"]['bicycle']["color"]`, ExpectedPaths: []string{"$['store']['bicycle']['color']"}},
+	}
+
+	for tcix, tc := range testCases {
+		stm := fmt.Sprintf("test case %d: QueryJSONPath(%q, store)", tcix, tc.Expr)
+		matches, err := QueryJSONPath(tc.Expr, store)
+
+		if msg, ok := tcore.TErr(stm, err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		var paths []string
+
+		for _, match := range matches {
+			paths = append(paths, match.Path)
+		}
+
+		if msg, ok := tcore.TAssertString(stm, strings.Join(paths, " "), strings.Join(tc.ExpectedPaths, " ")); !ok {
+			t.Error(msg)
+		}
+	}
+}
+
+func TestJSONPath_Values(t *testing.T) {
+	v := NewObjectValue(Object{
+		"a'b": NewArrayValue(Array{NewIntValue(1), NewFloatValue(1.5), NewStringValue("x"), NewValue(), NewBoolValue(true)}),
+	})
+
+	matches, err := QueryJSONPath(`$["a'b"][?@ == null || @ == true || @ > 1]`, v)
+
+	if msg, ok := tcore.TErr("QueryJSONPath", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertInt("len(matches)", len(matches), 3); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertString("matches[0].Path", matches[0].Path, `$['a\'b'][1]`); !ok {
+		t.Error(msg)
+	}
+
+	if !matches[0].Value.Equals(NewFloatValue(1.5)) || !matches[1].Value.IsNull() || !matches[2].Value.Bool() {
+		t.Errorf("unexpected matches %v", matches)
+	}
+}
+
+func TestCompileJSONPath_Errors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"store",
+		"$.",
+		"$[",
+		"$[01]",
+		"$[?@.a",
+		"$[?@..a == 1]",
+		"$[?1]",
+		"$[?length(@)]",
+		"$[?count(1) == 1]",
+		"$[?match(@.a, 'a') == true]",
+		"$['unterminated]",
+	} {
+		if _, err := CompileJSONPath(expr); err == nil {
+			t.Errorf("an error was expected when compiling %q", expr)
+		}
+	}
+}
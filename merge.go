@@ -0,0 +1,167 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+// ArrayStrategy says how Merge combines an Array in the base with an Array in the overlay
+type ArrayStrategy int
+
+const (
+	ArrayReplace      ArrayStrategy = iota // ArrayReplace uses the overlay Array in place of the base Array
+	ArrayAppend                            // ArrayAppend adds the overlay elements after the base elements
+	ArrayUnion                             // ArrayUnion appends only the overlay elements that the base lacks
+	ArrayMergeByIndex                      // ArrayMergeByIndex merges each overlay element into the base one at its index
+	ArrayMergeByKey                        // ArrayMergeByKey merges Object elements that share the value of a key member
+)
+
+// ArrayRule chooses an ArrayStrategy, along with the key member that ArrayMergeByKey uses to match elements
+type ArrayRule struct {
+	Strategy ArrayStrategy
+	Key      string
+}
+
+// MergeOptions controls Merge. The zero value replaces Arrays and lets the overlay win conflicts.
+type MergeOptions struct {
+	// Arrays is the rule for any Array that has no entry in ArrayRules
+	Arrays ArrayRule
+
+	// ArrayRules holds rules for specific Arrays, keyed by the JSON Pointer of the Array, e.g. /servers. A segment of *
+	// matches any key or index, e.g. /servers/*/ports for the ports of every server. An exact pointer wins over one
+	// with wildcards, and pointers with wildcards are tried in sorted order. A key that is not a valid JSON Pointer
+	// matches nothing.
+	ArrayRules map[string]ArrayRule
+
+	// OnConflict is called when base and overlay hold different Types at the same path, and returns the Value to keep.
+	// When it is nil the overlay wins. An error stops the merge and is returned by Merge.
+	OnConflict func(path Path, base, overlay Value) (Value, error)
+}
+
+// Merge returns a deep merge of overlay onto base. Objects are merged key by key, Arrays are combined according to the
+// ArrayRules in opts, and any other overlay value replaces the base value. Neither argument is modified.
+func Merge(base, overlay Value, opts MergeOptions) (Value, error) {
+	return mergeAt(base, overlay, Path{}, opts)
+}
+
+// Private
+
+func mergeAt(base, overlay Value, path Path, opts MergeOptions) (Value, error) {
+	if base.Type() != overlay.Type() {
+		if opts.OnConflict != nil {
+			return opts.OnConflict(path, base.Clone(), overlay.Clone())
+		}
+		return overlay.Clone(), nil
+	}
+
+	switch base.Type() {
+	case ObjectType:
+		result := base.Object().Clone()
+		for key, member := range overlay.Object() {
+			if baseMember, ok := result[key]; ok {
+				merged, err := mergeAt(baseMember, member, path.Key(key), opts)
+				if err != nil {
+					return Value{}, err
+				}
+				result[key] = merged
+			} else {
+				result[key] = member.Clone()
+			}
+		}
+		return NewObjectValue(result), nil
+	case ArrayType:
+		return mergeArrays(base.Array(), overlay.Array(), path, arrayRuleFor(path, opts), opts)
+	}
+
+	return overlay.Clone(), nil
+}
+
+// arrayRuleFor returns the ArrayRule for the Array at path
+func arrayRuleFor(path Path, opts MergeOptions) ArrayRule {
+	pointers := make([]string, 0, len(opts.ArrayRules))
+
+	for pointer := range opts.ArrayRules {
+		pointers = append(pointers, pointer)
+	}
+
+	if pointer, ok := matchPointer(path, pointers); ok {
+		return opts.ArrayRules[pointer]
+	}
+
+	return opts.Arrays
+}
+
+func mergeArrays(base, overlay Array, path Path, rule ArrayRule, opts MergeOptions) (Value, error) {
+	result := base.Clone()
+
+	switch rule.Strategy {
+	case ArrayAppend:
+		result = append(result, overlay.Clone()...)
+	case ArrayUnion:
+		for _, element := range overlay {
+			if !arrayContains(result, element) {
+				result = append(result, element.Clone())
+			}
+		}
+	case ArrayMergeByIndex:
+		for i, element := range overlay {
+			if i >= len(result) {
+				result = append(result, element.Clone())
+				continue
+			}
+			merged, err := mergeAt(result[i], element, path.Index(i), opts)
+			if err != nil {
+				return Value{}, err
+			}
+			result[i] = merged
+		}
+	case ArrayMergeByKey:
+		for _, element := range overlay {
+			i := indexByKey(result, element, rule.Key)
+			if i < 0 {
+				result = append(result, element.Clone())
+				continue
+			}
+			merged, err := mergeAt(result[i], element, path.Index(i), opts)
+			if err != nil {
+				return Value{}, err
+			}
+			result[i] = merged
+		}
+	default:
+		result = overlay.Clone()
+	}
+
+	return NewArrayValue(result), nil
+}
+
+func arrayContains(a Array, x Value) bool {
+	for i := range a {
+		if a[i].Equals(x) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// indexByKey returns the index of the Object element of a whose key member equals that of x, or -1
+func indexByKey(a Array, x Value, key string) int {
+	if x.Type() != ObjectType {
+		return -1
+	}
+
+	id, ok := x.Object()[key]
+
+	if !ok {
+		return -1
+	}
+
+	for i, element := range a {
+		if element.Type() != ObjectType {
+			continue
+		}
+		if candidate, ok := element.Object()[key]; ok && candidate.Equals(id) {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,220 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+// Conflict describes a location that ours and theirs both changed, in different ways, since base. The In fields say
+// whether the location exists on each side, since a deletion is a change too.
+type Conflict struct {
+	Path     Path
+	Base     Value
+	Ours     Value
+	Theirs   Value
+	InBase   bool
+	InOurs   bool
+	InTheirs bool
+}
+
+// Resolution is the outcome that a Resolver chooses for a Conflict, either a Value or the deletion of the location
+type Resolution struct {
+	Value   Value
+	Deleted bool
+}
+
+// Resolver decides a Conflict. It returns false to leave the conflict unresolved, in which case Merge3 reports it.
+type Resolver func(c Conflict) (Resolution, bool)
+
+// PreferOurs is a Resolver that resolves every conflict in favor of ours
+func PreferOurs(c Conflict) (Resolution, bool) {
+	return Resolution{Value: c.Ours, Deleted: !c.InOurs}, true
+}
+
+// PreferTheirs is a Resolver that resolves every conflict in favor of theirs
+func PreferTheirs(c Conflict) (Resolution, bool) {
+	return Resolution{Value: c.Theirs, Deleted: !c.InTheirs}, true
+}
+
+// Merge3Options controls Merge3With
+type Merge3Options struct {
+	// Resolver, when set, is offered each conflict before it is reported
+	Resolver Resolver
+
+	// ArrayKeys gives Arrays an identity for their elements, keyed by the JSON Pointer of the Array. The elements must
+	// be Objects, and those with equal values for the named member are treated as the same element, so that each side
+	// can add, remove and edit different elements. Other Arrays are merged only when one side left them unchanged.
+	// Pointers match as in MergeOptions.ArrayRules, so a segment of * matches any key or index.
+	ArrayKeys map[string]string
+}
+
+// Merge3 merges the changes that ours and theirs each made to base. A location changed on only one side takes that
+// side's value, Objects are merged key by key, and the remaining overlapping changes are returned as conflicts. The
+// merged Value holds ours at each conflicting location.
+func Merge3(base, ours, theirs Value) (Value, []Conflict) {
+	return Merge3With(base, ours, theirs, Merge3Options{})
+}
+
+// Merge3With is Merge3 with options
+func Merge3With(base, ours, theirs Value, opts Merge3Options) (Value, []Conflict) {
+	m := merger3{opts: opts}
+	result, _ := m.merge(Conflict{Path: Path{}, Base: base, Ours: ours, Theirs: theirs, InBase: true, InOurs: true,
+		InTheirs: true})
+	return result, m.conflicts
+}
+
+// Private
+
+type merger3 struct {
+	opts      Merge3Options
+	conflicts []Conflict
+}
+
+// merge returns the merged value at c.Path and whether the location exists after the merge
+func (m *merger3) merge(c Conflict) (Value, bool) {
+	switch {
+	case sameSide(c.InOurs, c.Ours, c.InTheirs, c.Theirs), sameSide(c.InBase, c.Base, c.InTheirs, c.Theirs):
+		return c.Ours.Clone(), c.InOurs
+	case sameSide(c.InBase, c.Base, c.InOurs, c.Ours):
+		return c.Theirs.Clone(), c.InTheirs
+	}
+
+	if c.InOurs && c.InTheirs && c.Ours.Type() == ObjectType && c.Theirs.Type() == ObjectType {
+		return m.mergeObjects(c), true
+	}
+
+	if key, ok := arrayKeyFor(c.Path, m.opts); ok && c.InOurs && c.InTheirs {
+		if merged, ok := m.mergeKeyedArrays(c, key); ok {
+			return merged, true
+		}
+	}
+
+	if m.opts.Resolver != nil {
+		if resolution, ok := m.opts.Resolver(c); ok {
+			return resolution.Value.Clone(), !resolution.Deleted
+		}
+	}
+
+	m.conflicts = append(m.conflicts, c)
+	return c.Ours.Clone(), c.InOurs
+}
+
+// arrayKeyFor returns the ArrayKeys member name for the Array at path
+func arrayKeyFor(path Path, opts Merge3Options) (string, bool) {
+	pointers := make([]string, 0, len(opts.ArrayKeys))
+
+	for pointer := range opts.ArrayKeys {
+		pointers = append(pointers, pointer)
+	}
+
+	if pointer, ok := matchPointer(path, pointers); ok {
+		return opts.ArrayKeys[pointer], true
+	}
+
+	return "", false
+}
+
+func (m *merger3) mergeObjects(c Conflict) Value {
+	var base Object
+
+	if c.InBase && c.Base.Type() == ObjectType {
+		base = c.Base.Object()
+	}
+
+	ours, theirs := c.Ours.Object(), c.Theirs.Object()
+	keys := NewObject(len(ours) + len(theirs))
+
+	for _, o := range []Object{base, ours, theirs} {
+		for key := range o {
+			keys[key] = Value{}
+		}
+	}
+
+	result := NewObject(len(keys))
+
+	for _, key := range sortedKeys(keys) {
+		child := Conflict{Path: c.Path.Key(key)}
+		child.Base, child.InBase = base[key]
+		child.Ours, child.InOurs = ours[key]
+		child.Theirs, child.InTheirs = theirs[key]
+
+		if merged, ok := m.merge(child); ok {
+			result[key] = merged
+		}
+	}
+
+	return NewObjectValue(result)
+}
+
+// mergeKeyedArrays merges Arrays whose elements are identified by a key member. The merged Array keeps the order of
+// ours, followed by the elements that only theirs has. It returns false if an element lacks the key.
+func (m *merger3) mergeKeyedArrays(c Conflict, key string) (Value, bool) {
+	if c.Ours.Type() != ArrayType || c.Theirs.Type() != ArrayType {
+		return Value{}, false
+	}
+
+	var base Array
+
+	if c.InBase && c.Base.Type() == ArrayType {
+		base = c.Base.Array()
+	}
+
+	ours, theirs := c.Ours.Array(), c.Theirs.Array()
+
+	for _, a := range []Array{base, ours, theirs} {
+		for _, element := range a {
+			if _, ok := elementKey(element, key); !ok {
+				return Value{}, false
+			}
+		}
+	}
+
+	result := NewArray()
+
+	for _, element := range ours {
+		id, _ := elementKey(element, key)
+		child := Conflict{Path: c.Path.Index(len(result)), Ours: element, InOurs: true}
+		child.Base, child.InBase = findByKey(base, key, id)
+		child.Theirs, child.InTheirs = findByKey(theirs, key, id)
+
+		if merged, ok := m.merge(child); ok {
+			result = append(result, merged)
+		}
+	}
+
+	for _, element := range theirs {
+		id, _ := elementKey(element, key)
+
+		if _, inOurs := findByKey(ours, key, id); inOurs {
+			continue
+		}
+
+		child := Conflict{Path: c.Path.Index(len(result)), Theirs: element, InTheirs: true}
+		child.Base, child.InBase = findByKey(base, key, id)
+
+		if merged, ok := m.merge(child); ok {
+			result = append(result, merged)
+		}
+	}
+
+	return NewArrayValue(result), true
+}
+
+func elementKey(element Value, key string) (Value, bool) {
+	if element.Type() != ObjectType {
+		return Value{}, false
+	}
+
+	id, ok := element.Object()[key]
+	return id, ok
+}
+
+func findByKey(a Array, key string, id Value) (Value, bool) {
+	if i := indexByKey(a, NewObjectValue(Object{key: id}), key); i >= 0 {
+		return a[i], true
+	}
+
+	return Value{}, false
+}
+
+// sameSide is true if two sides agree on both the existence and the value of a location
+func sameSide(aExists bool, a Value, bExists bool, b Value) bool {
+	return aExists == bExists && (!aExists || a.Equals(b))
+}
@@ -0,0 +1,132 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/webern/tcore"
+)
+
+func TestMerge3(t *testing.T) {
+	testCases := []struct {
+		Base              string
+		Ours              string
+		Theirs            string
+		Opts              Merge3Options
+		Expected          string
+		ExpectedConflicts []string
+	}{
+		{
+			Base:     `{"a": 1, "b": 2, "c": 3}`,
+			Ours:     `{"a": 10, "b": 2, "c": 3}`,
+			Theirs:   `{"a": 1, "b": 2, "d": 4}`,
+			Expected: `{"a": 10, "b": 2, "d": 4}`,
+		},
+		{
+			Base:     `{"a": {"x": 1, "y": 1}}`,
+			Ours:     `{"a": {"x": 2, "y": 1}}`,
+			Theirs:   `{"a": {"x": 1, "y": 2}}`,
+			Expected: `{"a": {"x": 2, "y": 2}}`,
+		},
+		{
+			Base:              `{"a": 1, "b": 1}`,
+			Ours:              `{"a": 2, "b": 1}`,
+			Theirs:            `{"a": 3}`,
+			Expected:          `{"a": 2}`,
+			ExpectedConflicts: []string{"/a"},
+		},
+		{
+			Base:              `{"a": 1}`,
+			Ours:              `{}`,
+			Theirs:            `{"a": 2}`,
+			Expected:          `{}`,
+			ExpectedConflicts: []string{"/a"},
+		},
+		{
+			Base:     `{"a": 1}`,
+			Ours:     `{}`,
+			Theirs:   `{"a": 2}`,
+			Opts:     Merge3Options{Resolver: PreferTheirs},
+			Expected: `{"a": 2}`,
+		},
+		{
+			Base:     `{"a": 1}`,
+			Ours:     `{"a": 2, "n": "x"}`,
+			Theirs:   `{"a": 3, "n": "x"}`,
+			Opts:     Merge3Options{Resolver: PreferOurs},
+			Expected: `{"a": 2, "n": "x"}`,
+		},
+		{
+			Base:              `{"l": [1, 2]}`,
+			Ours:              `{"l": [1, 2, 3]}`,
+			Theirs:            `{"l": [0, 1, 2]}`,
+			Expected:          `{"l": [1, 2, 3]}`,
+			ExpectedConflicts: []string{"/l"},
+		},
+		{
+			Base:   `{"l": [{"id": 1, "v": "a"}, {"id": 2, "v": "b"}, {"id": 3, "v": "c"}]}`,
+			Ours:   `{"l": [{"id": 1, "v": "A"}, {"id": 2, "v": "b"}, {"id": 3, "v": "c"}, {"id": 4, "v": "d"}]}`,
+			Theirs: `{"l": [{"id": 1, "v": "a"}, {"id": 3, "v": "C"}, {"id": 5, "v": "e"}]}`,
+			Opts:   Merge3Options{ArrayKeys: map[string]string{"/l": "id"}},
+			Expected: `{"l": [{"id": 1, "v": "A"}, {"id": 3, "v": "C"}, {"id": 4, "v": "d"},
+				{"id": 5, "v": "e"}]}`,
+		},
+		{
+			Base:              `{"l": [{"id": 1, "v": "a"}]}`,
+			Ours:              `{"l": [{"id": 1, "v": "b"}]}`,
+			Theirs:            `{"l": [{"id": 1, "v": "c"}]}`,
+			Opts:              Merge3Options{ArrayKeys: map[string]string{"/l": "id"}},
+			Expected:          `{"l": [{"id": 1, "v": "b"}]}`,
+			ExpectedConflicts: []string{"/l/0/v"},
+		},
+		{
+			Base:     `{"s": [{"n": "a", "p": [{"k": 1}]}, {"n": "b", "p": []}]}`,
+			Ours:     `{"s": [{"n": "a", "p": [{"k": 1}, {"k": 2}]}, {"n": "b", "p": []}]}`,
+			Theirs:   `{"s": [{"n": "a", "p": [{"k": 1}, {"k": 3}]}, {"n": "b", "p": [{"k": 4}]}]}`,
+			Opts:     Merge3Options{ArrayKeys: map[string]string{"/s": "n", "/s/*/p": "k"}},
+			Expected: `{"s": [{"n": "a", "p": [{"k": 1}, {"k": 2}, {"k": 3}]}, {"n": "b", "p": [{"k": 4}]}]}`,
+		},
+	}
+
+	for tcix, tc := range testCases {
+		stm := fmt.Sprintf("test case %d: Merge3With(%s, %s, %s)", tcix, tc.Base, tc.Ours, tc.Theirs)
+		actual, conflicts := Merge3With(mustParseJSON(t, tc.Base), mustParseJSON(t, tc.Ours),
+			mustParseJSON(t, tc.Theirs), tc.Opts)
+		expected := mustParseJSON(t, tc.Expected)
+
+		if msg, ok := tcore.TAssertBool(stm, actual.Equals(expected), true); !ok {
+			t.Error(msg + fmt.Sprintf(" - got %v", actual))
+		}
+
+		if msg, ok := tcore.TAssertInt(stm+" conflicts", len(conflicts), len(tc.ExpectedConflicts)); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		for i, path := range tc.ExpectedConflicts {
+			if msg, ok := tcore.TAssertString(stm+" conflict path", conflicts[i].Path.String(), path); !ok {
+				t.Error(msg)
+			}
+		}
+	}
+}
+
+func TestMerge3_ConflictSides(t *testing.T) {
+	_, conflicts := Merge3(mustParseJSON(t, `{"a": 1}`), mustParseJSON(t, `{}`), mustParseJSON(t, `{"a": 2}`))
+
+	if len(conflicts) != 1 {
+		t.Fatalf("expected 1 conflict but got %d", len(conflicts))
+	}
+
+	c := conflicts[0]
+
+	if !c.InBase || c.InOurs || !c.InTheirs {
+		t.Errorf("expected the key to exist in base and theirs only, got %v %v %v", c.InBase, c.InOurs, c.InTheirs)
+	}
+
+	if msg, ok := tcore.TAssertInt("c.Theirs.Int()", c.Theirs.Int(), 2); !ok {
+		t.Error(msg)
+	}
+}
@@ -0,0 +1,122 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/webern/tcore"
+)
+
+func TestMerge(t *testing.T) {
+	testCases := []struct {
+		Base     string
+		Overlay  string
+		Opts     MergeOptions
+		Expected string
+	}{
+		{
+			Base:     `{"a": 1, "b": {"c": 2, "d": 3}}`,
+			Overlay:  `{"b": {"d": 4, "e": 5}, "f": 6}`,
+			Expected: `{"a": 1, "b": {"c": 2, "d": 4, "e": 5}, "f": 6}`,
+		},
+		{
+			Base:     `{"a": [1, 2]}`,
+			Overlay:  `{"a": [3]}`,
+			Expected: `{"a": [3]}`,
+		},
+		{
+			Base:     `{"a": [1, 2]}`,
+			Overlay:  `{"a": [2, 3]}`,
+			Opts:     MergeOptions{Arrays: ArrayRule{Strategy: ArrayAppend}},
+			Expected: `{"a": [1, 2, 2, 3]}`,
+		},
+		{
+			Base:     `{"a": [1, 2]}`,
+			Overlay:  `{"a": [2, 3]}`,
+			Opts:     MergeOptions{Arrays: ArrayRule{Strategy: ArrayUnion}},
+			Expected: `{"a": [1, 2, 3]}`,
+		},
+		{
+			Base:     `{"a": [{"x": 1, "y": 1}, {"x": 2}]}`,
+			Overlay:  `{"a": [{"y": 2}, {"x": 3}, {"x": 4}]}`,
+			Opts:     MergeOptions{Arrays: ArrayRule{Strategy: ArrayMergeByIndex}},
+			Expected: `{"a": [{"x": 1, "y": 2}, {"x": 3}, {"x": 4}]}`,
+		},
+		{
+			Base:    `{"servers": [{"name": "a", "port": 1}, {"name": "b", "port": 2}], "tags": [1]}`,
+			Overlay: `{"servers": [{"name": "b", "port": 3}, {"name": "c", "port": 4}], "tags": [2]}`,
+			Opts: MergeOptions{ArrayRules: map[string]ArrayRule{
+				"/servers": {Strategy: ArrayMergeByKey, Key: "name"},
+			}},
+			Expected: `{"servers": [{"name": "a", "port": 1}, {"name": "b", "port": 3}, {"name": "c", "port": 4}], "tags": [2]}`,
+		},
+		{
+			Base:    `{"servers": [{"name": "a", "ports": [1]}], "ports": [1]}`,
+			Overlay: `{"servers": [{"name": "a", "ports": [2]}], "ports": [2]}`,
+			Opts: MergeOptions{ArrayRules: map[string]ArrayRule{
+				"/servers":         {Strategy: ArrayMergeByKey, Key: "name"},
+				"/servers/*/ports": {Strategy: ArrayAppend},
+			}},
+			Expected: `{"servers": [{"name": "a", "ports": [1, 2]}], "ports": [2]}`,
+		},
+		{
+			Base:     `{"a": {"b": 1}}`,
+			Overlay:  `{"a": "x"}`,
+			Expected: `{"a": "x"}`,
+		},
+		{
+			Base:    `{"a": {"b": 1}}`,
+			Overlay: `{"a": "x"}`,
+			Opts: MergeOptions{OnConflict: func(path Path, base, overlay Value) (Value, error) {
+				return base, nil
+			}},
+			Expected: `{"a": {"b": 1}}`,
+		},
+	}
+
+	for tcix, tc := range testCases {
+		base := mustParseJSON(t, tc.Base)
+		original := base.Clone()
+		stm := fmt.Sprintf("test case %d: Merge(%s, %s)", tcix, tc.Base, tc.Overlay)
+		actual, err := Merge(base, mustParseJSON(t, tc.Overlay), tc.Opts)
+
+		if msg, ok := tcore.TErr(stm, err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		expected := mustParseJSON(t, tc.Expected)
+
+		if msg, ok := tcore.TAssertBool(stm, actual.Equals(expected), true); !ok {
+			t.Error(msg + fmt.Sprintf(" - got %v", actual))
+		}
+
+		if msg, ok := tcore.TAssertBool(stm+" leaves the base unchanged", base.Equals(original), true); !ok {
+			t.Error(msg)
+		}
+	}
+}
+
+func TestMerge_ConflictError(t *testing.T) {
+	conflict := errors.New("conflict")
+	var conflictPath Path
+
+	_, err := Merge(mustParseJSON(t, `{"a": [{"b": 1}]}`), mustParseJSON(t, `{"a": [{"b": true}]}`), MergeOptions{
+		Arrays: ArrayRule{Strategy: ArrayMergeByIndex},
+		OnConflict: func(path Path, base, overlay Value) (Value, error) {
+			conflictPath = path
+			return Value{}, conflict
+		},
+	})
+
+	if err != conflict {
+		t.Errorf("expected the conflict error but got '%v'", err)
+	}
+
+	if msg, ok := tcore.TAssertString("conflictPath", conflictPath.String(), "/a/0/b"); !ok {
+		t.Error(msg)
+	}
+}
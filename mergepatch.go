@@ -0,0 +1,64 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+// MergePatch applies an RFC 7396 JSON Merge Patch to target and returns the result. When the patch is an Object, each
+// of its members is merged into target recursively and a Null member deletes the key. Any other patch, including an
+// Array, replaces target entirely. Neither argument is modified.
+func MergePatch(target, patch Value) Value {
+	if patch.Type() != ObjectType {
+		return patch.Clone()
+	}
+
+	var result Object
+
+	if target.Type() == ObjectType {
+		result = target.Object().Clone()
+	} else {
+		result = NewObject(len(patch.Object()))
+	}
+
+	for key, member := range patch.Object() {
+		if member.IsNull() {
+			delete(result, key)
+		} else {
+			result[key] = MergePatch(result[key], member)
+		}
+	}
+
+	return NewObjectValue(result)
+}
+
+// CreateMergePatch returns a JSON Merge Patch that transforms original into modified, such that
+// MergePatch(original, CreateMergePatch(original, modified)) equals modified. Because a Null in a merge patch means
+// delete, a Null member of modified cannot be represented and is removed rather than stored.
+func CreateMergePatch(original, modified Value) Value {
+	if original.Type() != ObjectType || modified.Type() != ObjectType {
+		return modified.Clone()
+	}
+
+	originalObject, modifiedObject := original.Object(), modified.Object()
+	patch := NewObject(0)
+
+	for key := range originalObject {
+		if _, ok := modifiedObject[key]; !ok {
+			patch[key] = NewValue()
+		}
+	}
+
+	for key, modifiedMember := range modifiedObject {
+		originalMember, ok := originalObject[key]
+
+		if !ok {
+			patch[key] = modifiedMember.Clone()
+		} else if originalMember.Type() == ObjectType && modifiedMember.Type() == ObjectType {
+			if member := CreateMergePatch(originalMember, modifiedMember); len(member.Object()) > 0 {
+				patch[key] = member
+			}
+		} else if !originalMember.Equals(modifiedMember) {
+			patch[key] = modifiedMember.Clone()
+		}
+	}
+
+	return NewObjectValue(patch)
+}
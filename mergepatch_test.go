@@ -0,0 +1,85 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/webern/tcore"
+)
+
+func TestMergePatch(t *testing.T) {
+	// the examples from appendix A of RFC 7396
+	testCases := []struct {
+		Target   string
+		Patch    string
+		Expected string
+	}{
+		{Target: `{"a":"b"}`, Patch: `{"a":"c"}`, Expected: `{"a":"c"}`},
+		{Target: `{"a":"b"}`, Patch: `{"b":"c"}`, Expected: `{"a":"b","b":"c"}`},
+		{Target: `{"a":"b"}`, Patch: `{"a":null}`, Expected: `{}`},
+		{Target: `{"a":"b","b":"c"}`, Patch: `{"a":null}`, Expected: `{"b":"c"}`},
+		{Target: `{"a":["b"]}`, Patch: `{"a":"c"}`, Expected: `{"a":"c"}`},
+		{Target: `{"a":"c"}`, Patch: `{"a":["b"]}`, Expected: `{"a":["b"]}`},
+		{Target: `{"a":{"b":"c"}}`, Patch: `{"a":{"b":"d","c":null}}`, Expected: `{"a":{"b":"d"}}`},
+		{Target: `{"a":[{"b":"c"}]}`, Patch: `{"a":[1]}`, Expected: `{"a":[1]}`},
+		{Target: `["a","b"]`, Patch: `["c","d"]`, Expected: `["c","d"]`},
+		{Target: `{"a":"b"}`, Patch: `["c"]`, Expected: `["c"]`},
+		{Target: `{"a":"foo"}`, Patch: `null`, Expected: `null`},
+		{Target: `{"a":"foo"}`, Patch: `"bar"`, Expected: `"bar"`},
+		{Target: `{"e":null}`, Patch: `{"a":1}`, Expected: `{"e":null,"a":1}`},
+		{Target: `[1,2]`, Patch: `{"a":"b","c":null}`, Expected: `{"a":"b"}`},
+		{Target: `{}`, Patch: `{"a":{"bb":{"ccc":null}}}`, Expected: `{"a":{"bb":{}}}`},
+	}
+
+	for tcix, tc := range testCases {
+		target := mustParseJSON(t, tc.Target)
+		original := target.Clone()
+		expected := mustParseJSON(t, tc.Expected)
+		stm := fmt.Sprintf("test case %d: MergePatch(%s, %s)", tcix, tc.Target, tc.Patch)
+		actual := MergePatch(target, mustParseJSON(t, tc.Patch))
+
+		if msg, ok := tcore.TAssertBool(stm, actual.Equals(expected), true); !ok {
+			t.Error(msg + fmt.Sprintf(" - got %v", actual))
+		}
+
+		if msg, ok := tcore.TAssertBool(stm+" leaves the target unchanged", target.Equals(original), true); !ok {
+			t.Error(msg)
+		}
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	testCases := []struct {
+		Original string
+		Modified string
+		Expected string
+	}{
+		{Original: `{"a":1}`, Modified: `{"a":1}`, Expected: `{}`},
+		{Original: `{"a":1,"b":2}`, Modified: `{"a":3}`, Expected: `{"a":3,"b":null}`},
+		{Original: `{"a":{"b":1,"c":2}}`, Modified: `{"a":{"b":1,"c":3,"d":4}}`, Expected: `{"a":{"c":3,"d":4}}`},
+		{Original: `{"a":{"b":1}}`, Modified: `{"a":{"b":1}}`, Expected: `{}`},
+		{Original: `{"a":[1,2]}`, Modified: `{"a":[1,2,3]}`, Expected: `{"a":[1,2,3]}`},
+		{Original: `{"a":"x"}`, Modified: `{"a":{"b":1}}`, Expected: `{"a":{"b":1}}`},
+		{Original: `[1]`, Modified: `{"a":1}`, Expected: `{"a":1}`},
+	}
+
+	for tcix, tc := range testCases {
+		original := mustParseJSON(t, tc.Original)
+		modified := mustParseJSON(t, tc.Modified)
+		expected := mustParseJSON(t, tc.Expected)
+		stm := fmt.Sprintf("test case %d: CreateMergePatch(%s, %s)", tcix, tc.Original, tc.Modified)
+		patch := CreateMergePatch(original, modified)
+
+		if msg, ok := tcore.TAssertBool(stm, patch.Equals(expected), true); !ok {
+			t.Error(msg + fmt.Sprintf(" - got %v", patch))
+		}
+
+		merged := MergePatch(original, patch)
+
+		if msg, ok := tcore.TAssertBool(stm+" round trip", merged.Equals(modified), true); !ok {
+			t.Error(msg + fmt.Sprintf(" - got %v", merged))
+		}
+	}
+}
@@ -61,6 +61,12 @@ func (o Object) Clone() Object {
 //	return b.Bytes(), nil
 //}
 
+// MarshalJSON marshals the object as a JSON object. It is needed because Object also implements encoding.TextMarshaler,
+// which encoding/json would otherwise prefer over the default map encoding.
+func (o Object) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]Value(o))
+}
+
 func (o *Object) UnmarshalJSON(data []byte) error {
 	s := string(data)
 	s = strings.TrimSpace(s)
@@ -0,0 +1,247 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PatchError reports which operation of a JSON Patch failed and why
+type PatchError struct {
+	Index int    // the position of the failing operation in the patch
+	Op    string // the op member of the failing operation
+	Err   error
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("patch operation %d (%s): %s", e.Index, e.Op, e.Err.Error())
+}
+
+// Unwrap returns the reason that the operation failed
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// ErrPatchTestFailed is held by a PatchError when a test operation finds a different value
+var ErrPatchTestFailed = errors.New("the test operation failed")
+
+// ApplyPatch applies an RFC 6902 JSON Patch, given as an Array of operation Objects, to a copy of doc. The add, remove,
+// replace, move, copy and test operations are supported, and test compares values with Equals. The patch is atomic: if
+// any operation fails, the error is returned along with doc unchanged.
+func ApplyPatch(doc Value, patch Array) (Value, error) {
+	result := doc.Clone()
+
+	for i, operation := range patch {
+		o := operation.Object()
+		op := o["op"].String()
+
+		if err := applyPatchOperation(&result, op, o); err != nil {
+			return doc, &PatchError{Index: i, Op: op, Err: err}
+		}
+	}
+
+	return result, nil
+}
+
+// CreatePatch returns a JSON Patch that transforms from into to. Objects are compared key by key and Arrays by their
+// longest common subsequence, so that unchanged elements are not rewritten.
+func CreatePatch(from, to Value) Array {
+	return appendPatch(NewArray(), from, to, Path{})
+}
+
+// Private
+
+func applyPatchOperation(doc *Value, op string, o Object) error {
+	path, ok := o["path"]
+
+	if !ok || !path.IsString() {
+		return errors.New("the operation has no path")
+	}
+
+	value, hasValue := o["value"]
+	from, hasFrom := o["from"]
+
+	if (op == "add" || op == "replace" || op == "test") && !hasValue {
+		return errors.New("the operation has no value")
+	} else if (op == "move" || op == "copy") && (!hasFrom || !from.IsString()) {
+		return errors.New("the operation has no from")
+	}
+
+	switch op {
+	case "add":
+		return addAt(doc, path.String(), value.Clone())
+	case "remove":
+		if path.String() == "" {
+			return errors.New("the root cannot be removed")
+		}
+		return doc.Delete(path.String())
+	case "replace":
+		if _, err := doc.Get(path.String()); err != nil {
+			return err
+		}
+		return doc.Set(path.String(), value.Clone())
+	case "move":
+		if path.String() == from.String() {
+			return nil
+		}
+		if strings.HasPrefix(path.String(), from.String()+"/") {
+			return errors.New("a value cannot be moved into one of its own children")
+		}
+		moved, err := doc.Get(from.String())
+		if err != nil {
+			return err
+		}
+		if err = doc.Delete(from.String()); err != nil {
+			return err
+		}
+		return addAt(doc, path.String(), moved)
+	case "copy":
+		copied, err := doc.Get(from.String())
+		if err != nil {
+			return err
+		}
+		return addAt(doc, path.String(), copied.Clone())
+	case "test":
+		actual, err := doc.Get(path.String())
+		if err != nil {
+			return err
+		}
+		if !actual.Equals(value) {
+			return ErrPatchTestFailed
+		}
+		return nil
+	}
+
+	return fmt.Errorf("the op %q is not supported", op)
+}
+
+// addAt implements the add operation, which inserts into arrays rather than replacing elements
+func addAt(doc *Value, pointer string, x Value) error {
+	path, err := ParsePointer(pointer)
+
+	if err != nil {
+		return err
+	}
+
+	if len(path) == 0 {
+		*doc = x
+		return nil
+	}
+
+	return updatePath(doc, pointer, path, func(parent *Value, last string) error {
+		switch parent.Type() {
+		case ObjectType:
+			parent.obj[last] = x
+		case ArrayType:
+			i, ok := arrayIndex(last, len(parent.arr)+1)
+			if last == "-" {
+				i, ok = len(parent.arr), true
+			}
+			if !ok {
+				return ErrPathIndex
+			}
+			parent.arr = append(parent.arr, Value{})
+			copy(parent.arr[i+1:], parent.arr[i:])
+			parent.arr[i] = x
+		default:
+			return ErrPathTypeMismatch
+		}
+		return nil
+	})
+}
+
+func patchOperation(op string, path Path, value *Value) Value {
+	o := Object{
+		"op":   NewStringValue(op),
+		"path": NewStringValue(path.String()),
+	}
+
+	if value != nil {
+		o["value"] = value.Clone()
+	}
+
+	return NewObjectValue(o)
+}
+
+func appendPatch(patch Array, from, to Value, path Path) Array {
+	if from.Type() != to.Type() {
+		return append(patch, patchOperation("replace", path, &to))
+	}
+
+	switch from.Type() {
+	case ObjectType:
+		fromObject, toObject := from.Object(), to.Object()
+		for _, key := range sortedKeys(fromObject) {
+			if toValue, ok := toObject[key]; ok {
+				patch = appendPatch(patch, fromObject[key], toValue, path.Key(key))
+			} else {
+				patch = append(patch, patchOperation("remove", path.Key(key), nil))
+			}
+		}
+		for _, key := range sortedKeys(toObject) {
+			if _, ok := fromObject[key]; !ok {
+				toValue := toObject[key]
+				patch = append(patch, patchOperation("add", path.Key(key), &toValue))
+			}
+		}
+		return patch
+	case ArrayType:
+		return appendArrayPatch(patch, from.Array(), to.Array(), path)
+	}
+
+	if !from.Equals(to) {
+		patch = append(patch, patchOperation("replace", path, &to))
+	}
+
+	return patch
+}
+
+// appendArrayPatch keeps the longest common subsequence of the two arrays in place. Elements that are replaced by a
+// single other element are patched recursively, and the rest are added or removed.
+func appendArrayPatch(patch Array, from, to Array, path Path) Array {
+	n, m := len(from), len(to)
+	lcs := make([][]int, n+1)
+
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if from[i].Equals(to[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j, position := 0, 0, 0
+
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && from[i].Equals(to[j]) && lcs[i][j] == lcs[i+1][j+1]+1:
+			i++
+			j++
+			position++
+		case i < n && j < m && lcs[i][j] == lcs[i+1][j+1]:
+			patch = appendPatch(patch, from[i], to[j], path.Index(position))
+			i++
+			j++
+			position++
+		case j < m && (i == n || lcs[i][j+1] >= lcs[i+1][j]):
+			patch = append(patch, patchOperation("add", path.Index(position), &to[j]))
+			j++
+			position++
+		default:
+			patch = append(patch, patchOperation("remove", path.Index(position), nil))
+			i++
+		}
+	}
+
+	return patch
+}
@@ -0,0 +1,174 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/webern/tcore"
+)
+
+func mustParseJSON(t *testing.T, s string) Value {
+	var v Value
+
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("unable to parse %s: %s", s, err.Error())
+	}
+
+	return v
+}
+
+func TestApplyPatch(t *testing.T) {
+	testCases := []struct {
+		Doc             string
+		Patch           string
+		Expected        string
+		IsErrorExpected bool
+	}{
+		{
+			Doc:      `{"foo": "bar"}`,
+			Patch:    `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			Expected: `{"baz": "qux", "foo": "bar"}`,
+		},
+		{
+			Doc:      `{"foo": ["bar", "baz"]}`,
+			Patch:    `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			Expected: `{"foo": ["bar", "qux", "baz"]}`,
+		},
+		{
+			Doc:      `{"foo": ["bar", "baz"]}`,
+			Patch:    `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			Expected: `{"foo": ["bar", "baz", ["abc", "def"]]}`,
+		},
+		{
+			Doc:      `{"baz": "qux", "foo": "bar"}`,
+			Patch:    `[{"op": "remove", "path": "/baz"}]`,
+			Expected: `{"foo": "bar"}`,
+		},
+		{
+			Doc:      `{"baz": "qux", "foo": "bar"}`,
+			Patch:    `[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			Expected: `{"baz": "boo", "foo": "bar"}`,
+		},
+		{
+			Doc:      `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			Patch:    `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			Expected: `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{
+			Doc:      `{"foo": ["all", "grass", "cows", "eat"]}`,
+			Patch:    `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			Expected: `{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		{
+			Doc:      `{"foo": {"bar": [1]}}`,
+			Patch:    `[{"op": "copy", "from": "/foo/bar", "path": "/baz"}, {"op": "add", "path": "/baz/0", "value": 0}]`,
+			Expected: `{"foo": {"bar": [1]}, "baz": [0, 1]}`,
+		},
+		{
+			Doc:      `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			Patch:    `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`,
+			Expected: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{
+			Doc:             `{"baz": "qux"}`,
+			Patch:           `[{"op": "remove", "path": "/baz"}, {"op": "test", "path": "/baz", "value": "bar"}]`,
+			IsErrorExpected: true,
+		},
+		{
+			Doc:             `{"foo": "bar"}`,
+			Patch:           `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			IsErrorExpected: true,
+		},
+		{
+			Doc:             `{"foo": "bar"}`,
+			Patch:           `[{"op": "replace", "path": "/baz", "value": "qux"}]`,
+			IsErrorExpected: true,
+		},
+		{
+			Doc:             `{"foo": {"bar": 1}}`,
+			Patch:           `[{"op": "move", "from": "/foo", "path": "/foo/bar/baz"}]`,
+			IsErrorExpected: true,
+		},
+		{
+			Doc:             `{"foo": "bar"}`,
+			Patch:           `[{"op": "frobnicate", "path": "/foo"}]`,
+			IsErrorExpected: true,
+		},
+	}
+
+	for tcix, tc := range testCases {
+		doc := mustParseJSON(t, tc.Doc)
+		original := doc.Clone()
+		stm := fmt.Sprintf("test case %d: ApplyPatch(doc, patch)", tcix)
+		actual, err := ApplyPatch(doc, mustParseJSON(t, tc.Patch).Array())
+
+		if tc.IsErrorExpected {
+			if _, ok := err.(*PatchError); !ok {
+				t.Errorf("%s - expected a *PatchError but got '%v'", stm, err)
+			}
+			if !actual.Equals(original) {
+				t.Errorf("%s - the document should be unchanged after a failed patch but is %v", stm, actual)
+			}
+			continue
+		}
+
+		if msg, ok := tcore.TErr(stm, err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		expected := mustParseJSON(t, tc.Expected)
+
+		if msg, ok := tcore.TAssertBool(stm, actual.Equals(expected), true); !ok {
+			t.Error(msg + fmt.Sprintf(" - '%v' != '%v'", actual, expected))
+		}
+
+		if !doc.Equals(original) {
+			t.Errorf("%s - the input document was modified", stm)
+		}
+	}
+}
+
+func TestCreatePatch(t *testing.T) {
+	testCases := []struct {
+		From            string
+		To              string
+		ExpectedOpCount int
+	}{
+		{From: `{"a": 1}`, To: `{"a": 1}`, ExpectedOpCount: 0},
+		{From: `{"a": 1, "b": 2}`, To: `{"a": 3, "c": 2}`, ExpectedOpCount: 3},
+		{From: `[1, 2, 3, 4]`, To: `[0, 1, 3, 4, 5]`, ExpectedOpCount: 3},
+		{From: `[{"id": 1, "n": "a"}, {"id": 2}]`, To: `[{"id": 1, "n": "b"}, {"id": 2}]`, ExpectedOpCount: 1},
+		{From: `{"a": [1, 2]}`, To: `{"a": "x"}`, ExpectedOpCount: 1},
+		{From: `{"a": {"b": {"c": true}}}`, To: `{"a": {"b": {"c": false, "d": null}}}`, ExpectedOpCount: 2},
+		{From: `[]`, To: `[1, 2]`, ExpectedOpCount: 2},
+		{From: `[1, 2]`, To: `[]`, ExpectedOpCount: 2},
+	}
+
+	for tcix, tc := range testCases {
+		from := mustParseJSON(t, tc.From)
+		to := mustParseJSON(t, tc.To)
+		patch := CreatePatch(from, to)
+		stm := fmt.Sprintf("test case %d: len(CreatePatch(from, to))", tcix)
+
+		if msg, ok := tcore.TAssertInt(stm, len(patch), tc.ExpectedOpCount); !ok {
+			b, _ := json.Marshal(patch)
+			t.Error(msg + " - " + string(b))
+		}
+
+		stm = fmt.Sprintf("test case %d: ApplyPatch(from, patch)", tcix)
+		actual, err := ApplyPatch(from, patch)
+
+		if msg, ok := tcore.TErr(stm, err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		if msg, ok := tcore.TAssertBool(stm, actual.Equals(to), true); !ok {
+			t.Error(msg + fmt.Sprintf(" - '%v' != '%v'", actual, to))
+		}
+	}
+}
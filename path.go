@@ -0,0 +1,101 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Path identifies a location in a Value tree as the list of object keys and array indices leading to it from the root.
+// Array indices are written in decimal, so Path{"a", "2", "b"} is the "b" property of the third element of "a". The
+// empty Path refers to the root itself.
+type Path []string
+
+// Key returns a new Path that extends p with an object key
+func (p Path) Key(key string) Path {
+	newPath := make(Path, len(p), len(p)+1)
+	copy(newPath, p)
+	return append(newPath, key)
+}
+
+// Index returns a new Path that extends p with an array index
+func (p Path) Index(index int) Path {
+	return p.Key(strconv.Itoa(index))
+}
+
+// String renders the Path as an RFC 6901 JSON Pointer, e.g. /a/2/b
+func (p Path) String() string {
+	b := strings.Builder{}
+
+	for _, segment := range p {
+		b.WriteString("/")
+		b.WriteString(pointerEscaper.Replace(segment))
+	}
+
+	return b.String()
+}
+
+// Private
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// matchPath reports whether path matches pattern, in which a segment of * matches any single segment
+func matchPath(pattern, path Path) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+
+	for i, segment := range pattern {
+		if segment != "*" && segment != path[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchPointer returns the JSON Pointer among pointers that selects path. An exact pointer wins over one with *
+// segments, which are tried in sorted order. A pointer that does not parse matches nothing.
+func matchPointer(path Path, pointers []string) (string, bool) {
+	exact := path.String()
+
+	for _, pointer := range pointers {
+		if pointer == exact {
+			return pointer, true
+		}
+	}
+
+	sorted := append([]string{}, pointers...)
+	sort.Strings(sorted)
+
+	for _, pointer := range sorted {
+		if pattern, err := ParsePointer(pointer); err == nil && matchPath(pattern, path) {
+			return pointer, true
+		}
+	}
+
+	return "", false
+}
+
+// arrayIndex parses a Path segment as an index into an array of the given length
+func arrayIndex(segment string, length int) (int, bool) {
+	if len(segment) == 0 || (len(segment) > 1 && segment[0] == '0') {
+		return 0, false
+	}
+
+	for _, c := range segment {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+
+	i, err := strconv.Atoi(segment)
+
+	if err != nil || i < 0 || i >= length {
+		return 0, false
+	}
+
+	return i, true
+}
@@ -0,0 +1,222 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// These errors are held in the Err field of a PathError to say why the path could not be followed
+var (
+	ErrPathSyntax       = errors.New("the path is malformed")
+	ErrPathNotFound     = errors.New("the object has no such key")
+	ErrPathIndex        = errors.New("the array index is malformed or out of range")
+	ErrPathTypeMismatch = errors.New("the value is not an object or array")
+)
+
+// PathError reports the segment of a path at which a lookup or modification failed
+type PathError struct {
+	Path    string // the path as it was given
+	Segment string // the failing segment, unescaped
+	Index   int    // the position of the failing segment in the path, starting at zero
+	Err     error  // one of the ErrPath errors
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("%q: segment %d (%q): %s", e.Path, e.Index, e.Segment, e.Err.Error())
+}
+
+// Unwrap returns the reason that the path could not be followed
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// ParsePointer parses an RFC 6901 JSON Pointer, such as /a/2/b, into a Path. The escape sequences ~0 and ~1 stand for
+// ~ and / respectively. The empty pointer refers to the root.
+func ParsePointer(pointer string) (Path, error) {
+	if pointer == "" {
+		return Path{}, nil
+	}
+
+	if pointer[0] != '/' {
+		return nil, &PathError{Path: pointer, Segment: pointer, Index: 0, Err: ErrPathSyntax}
+	}
+
+	segments := strings.Split(pointer[1:], "/")
+	path := make(Path, len(segments))
+
+	for i, segment := range segments {
+		for j := 0; j < len(segment); j++ {
+			if segment[j] == '~' && (j+1 == len(segment) || (segment[j+1] != '0' && segment[j+1] != '1')) {
+				return nil, &PathError{Path: pointer, Segment: segment, Index: i, Err: ErrPathSyntax}
+			}
+		}
+		path[i] = pointerUnescaper.Replace(segment)
+	}
+
+	return path, nil
+}
+
+// Get returns the Value that the JSON Pointer refers to
+func (v Value) Get(pointer string) (Value, error) {
+	path, err := ParsePointer(pointer)
+
+	if err != nil {
+		return Value{}, err
+	}
+
+	return getPath(v, pointer, path)
+}
+
+// Exists returns true if the JSON Pointer refers to a Value
+func (v Value) Exists(pointer string) bool {
+	_, err := v.Get(pointer)
+	return err == nil
+}
+
+// Set stores x at the location that the JSON Pointer refers to. An object property is added or replaced, an array
+// element is replaced, and the index - appends to an array. The containers above the location must already exist. The
+// change is made in place, so it is visible through v even when it happens inside a nested Array.
+func (v *Value) Set(pointer string, x Value) error {
+	path, err := ParsePointer(pointer)
+
+	if err != nil {
+		return err
+	}
+
+	if len(path) == 0 {
+		*v = x
+		return nil
+	}
+
+	return updatePath(v, pointer, path, func(parent *Value, last string) error {
+		switch parent.Type() {
+		case ObjectType:
+			parent.obj[last] = x
+		case ArrayType:
+			if last == "-" {
+				parent.arr = append(parent.arr, x)
+			} else if i, ok := arrayIndex(last, len(parent.arr)); ok {
+				parent.arr[i] = x
+			} else {
+				return ErrPathIndex
+			}
+		default:
+			return ErrPathTypeMismatch
+		}
+		return nil
+	})
+}
+
+// Delete removes the object property or array element that the JSON Pointer refers to. Later array elements move down
+// to fill the gap.
+func (v *Value) Delete(pointer string) error {
+	path, err := ParsePointer(pointer)
+
+	if err != nil {
+		return err
+	}
+
+	if len(path) == 0 {
+		v.SetNull()
+		return nil
+	}
+
+	return updatePath(v, pointer, path, removeChild)
+}
+
+// Private
+
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// child returns the member of an object or element of an array named by one path segment
+func child(v Value, segment string) (Value, error) {
+	switch v.Type() {
+	case ObjectType:
+		if c, ok := v.obj[segment]; ok {
+			return c, nil
+		}
+		return Value{}, ErrPathNotFound
+	case ArrayType:
+		if i, ok := arrayIndex(segment, len(v.arr)); ok {
+			return v.arr[i], nil
+		}
+		return Value{}, ErrPathIndex
+	}
+
+	return Value{}, ErrPathTypeMismatch
+}
+
+// removeChild deletes an object property or array element from parent
+func removeChild(parent *Value, last string) error {
+	switch parent.Type() {
+	case ObjectType:
+		if _, ok := parent.obj[last]; !ok {
+			return ErrPathNotFound
+		}
+		delete(parent.obj, last)
+	case ArrayType:
+		i, ok := arrayIndex(last, len(parent.arr))
+		if !ok {
+			return ErrPathIndex
+		}
+		parent.arr = append(parent.arr[:i], parent.arr[i+1:]...)
+	default:
+		return ErrPathTypeMismatch
+	}
+
+	return nil
+}
+
+func getPath(v Value, expr string, path Path) (Value, error) {
+	current := v
+
+	for i, segment := range path {
+		c, err := child(current, segment)
+
+		if err != nil {
+			return Value{}, &PathError{Path: expr, Segment: segment, Index: i, Err: err}
+		}
+
+		current = c
+	}
+
+	return current, nil
+}
+
+// updatePath walks down to the container holding the last segment of a non-empty path and calls fn with a pointer to
+// it. Each container on the way is written back into its parent, so changes that fn makes to the length of an Array
+// are visible from the root. An error returned by fn is reported as a PathError for the last segment.
+func updatePath(v *Value, expr string, path Path, fn func(parent *Value, last string) error) error {
+	return updatePathFrom(v, expr, path, 0, fn)
+}
+
+func updatePathFrom(v *Value, expr string, path Path, depth int, fn func(parent *Value, last string) error) error {
+	segment := path[depth]
+
+	if depth == len(path)-1 {
+		if err := fn(v, segment); err != nil {
+			return &PathError{Path: expr, Segment: segment, Index: depth, Err: err}
+		}
+		return nil
+	}
+
+	c, err := child(*v, segment)
+
+	if err != nil {
+		return &PathError{Path: expr, Segment: segment, Index: depth, Err: err}
+	}
+
+	err = updatePathFrom(&c, expr, path, depth+1, fn)
+
+	if v.Type() == ObjectType {
+		v.obj[segment] = c
+	} else {
+		i, _ := arrayIndex(segment, len(v.arr))
+		v.arr[i] = c
+	}
+
+	return err
+}
@@ -0,0 +1,170 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/webern/tcore"
+)
+
+// rfc6901Document is the example document from section 5 of RFC 6901
+const rfc6901Document = `{
+	"foo": ["bar", "baz"],
+	"": 0,
+	"a/b": 1,
+	"c%d": 2,
+	"e^f": 3,
+	"g|h": 4,
+	"i\\j": 5,
+	"k\"l": 6,
+	" ": 7,
+	"m~n": 8
+}`
+
+func newTestPointerDocument(t *testing.T) Value {
+	var v Value
+
+	if err := json.Unmarshal([]byte(rfc6901Document), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	return v
+}
+
+func TestValue_Get(t *testing.T) {
+	doc := newTestPointerDocument(t)
+
+	testCases := []struct {
+		Pointer  string
+		Expected Value
+		Err      error
+	}{
+		{Pointer: "", Expected: doc},
+		{Pointer: "/foo", Expected: NewArrayValue(Array{NewStringValue("bar"), NewStringValue("baz")})},
+		{Pointer: "/foo/0", Expected: NewStringValue("bar")},
+		{Pointer: "/", Expected: NewIntValue(0)},
+		{Pointer: "/a~1b", Expected: NewIntValue(1)},
+		{Pointer: "/c%d", Expected: NewIntValue(2)},
+		{Pointer: "/i\\j", Expected: NewIntValue(5)},
+		{Pointer: "/ ", Expected: NewIntValue(7)},
+		{Pointer: "/m~0n", Expected: NewIntValue(8)},
+		{Pointer: "/missing", Err: ErrPathNotFound},
+		{Pointer: "/foo/2", Err: ErrPathIndex},
+		{Pointer: "/foo/01", Err: ErrPathIndex},
+		{Pointer: "/foo/-", Err: ErrPathIndex},
+		{Pointer: "/foo/0/x", Err: ErrPathTypeMismatch},
+		{Pointer: "foo", Err: ErrPathSyntax},
+		{Pointer: "/m~2n", Err: ErrPathSyntax},
+	}
+
+	for tcix, tc := range testCases {
+		stm := fmt.Sprintf("test case %d: doc.Get(%q)", tcix, tc.Pointer)
+		actual, err := doc.Get(tc.Pointer)
+
+		if tc.Err != nil {
+			if pathErr, ok := err.(*PathError); !ok || pathErr.Err != tc.Err {
+				t.Errorf("%s - expected a PathError holding '%v' but got '%v'", stm, tc.Err, err)
+			}
+			if doc.Exists(tc.Pointer) {
+				t.Errorf("%s - Exists should be false", stm)
+			}
+			continue
+		}
+
+		if msg, ok := tcore.TErr(stm, err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		if msg, ok := tcore.TAssertBool(stm, actual.Equals(tc.Expected), true); !ok {
+			t.Error(msg)
+		}
+	}
+}
+
+func TestValue_SetAndDelete(t *testing.T) {
+	v := NewObjectValue(Object{
+		"a": NewArrayValue(Array{
+			NewObjectValue(Object{"b": NewArrayValue(Array{NewIntValue(1)})}),
+		}),
+	})
+
+	if err := v.Set("/a/0/b/-", NewIntValue(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Set("/a/0/b/0", NewIntValue(0)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Set("/a/-", NewStringValue("x")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Set("/c", NewBoolValue(true)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := NewObjectValue(Object{
+		"a": NewArrayValue(Array{
+			NewObjectValue(Object{"b": NewArrayValue(Array{NewIntValue(0), NewIntValue(2)})}),
+			NewStringValue("x"),
+		}),
+		"c": NewBoolValue(true),
+	})
+
+	if msg, ok := tcore.TAssertBool("v.Equals(expected)", v.Equals(expected), true); !ok {
+		t.Errorf("%s - got %v", msg, v)
+	}
+
+	if err := v.Delete("/a/0/b/0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Delete("/c"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected = NewObjectValue(Object{
+		"a": NewArrayValue(Array{
+			NewObjectValue(Object{"b": NewArrayValue(Array{NewIntValue(2)})}),
+			NewStringValue("x"),
+		}),
+	})
+
+	if msg, ok := tcore.TAssertBool("v.Equals(expected)", v.Equals(expected), true); !ok {
+		t.Errorf("%s - got %v", msg, v)
+	}
+
+	err := v.Set("/x/y", NewIntValue(1))
+
+	if pathErr, ok := err.(*PathError); !ok || pathErr.Index != 0 || pathErr.Segment != "x" {
+		t.Errorf("expected a PathError naming segment 0 \"x\" but got '%v'", err)
+	}
+
+	if err = v.Delete("/a/5"); err == nil {
+		t.Error("an error was expected when deleting past the end of an array")
+	}
+}
+
+func TestPath_String(t *testing.T) {
+	path := Path{"a/b", "m~n", "2"}
+
+	if msg, ok := tcore.TAssertString("path.String()", path.String(), "/a~1b/m~0n/2"); !ok {
+		t.Error(msg)
+	}
+
+	parsed, err := ParsePointer(path.String())
+
+	if msg, ok := tcore.TErr("ParsePointer", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if !reflect.DeepEqual(parsed, path) {
+		t.Errorf("ParsePointer(path.String()) = %q, want %q", parsed, path)
+	}
+}
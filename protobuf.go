@@ -0,0 +1,559 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProtoOptions controls UnmarshalProtoValueWith, UnmarshalProtoStructWith and UnmarshalProtoListValueWith
+type ProtoOptions struct {
+	// ParseTimestamps turns each string that ParseProtoTimestamp accepts into a Time. This reverses the way that
+	// MarshalProtoValue writes a Time, but it also converts any other string that happens to hold a timestamp.
+	ParseTimestamps bool
+}
+
+// MarshalProtoValue encodes v in the protobuf binary wire format of the google.protobuf.Value message. Protobuf has a
+// single double-precision number type, so Int is encoded as a double, and an Int beyond ±2^53, which a double cannot
+// hold exactly, is an error. Time is encoded as a string in the proto3 JSON format for google.protobuf.Timestamp, and
+// Bytes as a string in standard base64.
+func MarshalProtoValue(v Value) ([]byte, error) {
+	return appendProtoValue(nil, v)
+}
+
+// UnmarshalProtoValue decodes the protobuf binary wire format of a google.protobuf.Value message. Numbers that hold an
+// integral value become Int, just as they do when unmarshalling JSON.
+func UnmarshalProtoValue(data []byte) (Value, error) {
+	return decodeProtoValue(data, ProtoOptions{})
+}
+
+// UnmarshalProtoValueWith is UnmarshalProtoValue with options
+func UnmarshalProtoValueWith(data []byte, opts ProtoOptions) (Value, error) {
+	return decodeProtoValue(data, opts)
+}
+
+// MarshalProtoStruct encodes o in the protobuf binary wire format of the google.protobuf.Struct message. Fields are
+// written in key order so that the output is deterministic.
+func MarshalProtoStruct(o Object) ([]byte, error) {
+	return appendProtoStruct(nil, o)
+}
+
+// UnmarshalProtoStruct decodes the protobuf binary wire format of a google.protobuf.Struct message
+func UnmarshalProtoStruct(data []byte) (Object, error) {
+	return decodeProtoStruct(data, ProtoOptions{})
+}
+
+// UnmarshalProtoStructWith is UnmarshalProtoStruct with options
+func UnmarshalProtoStructWith(data []byte, opts ProtoOptions) (Object, error) {
+	return decodeProtoStruct(data, opts)
+}
+
+// MarshalProtoListValue encodes a in the protobuf binary wire format of the google.protobuf.ListValue message
+func MarshalProtoListValue(a Array) ([]byte, error) {
+	return appendProtoList(nil, a)
+}
+
+// UnmarshalProtoListValue decodes the protobuf binary wire format of a google.protobuf.ListValue message
+func UnmarshalProtoListValue(data []byte) (Array, error) {
+	return decodeProtoList(data, ProtoOptions{})
+}
+
+// UnmarshalProtoListValueWith is UnmarshalProtoListValue with options
+func UnmarshalProtoListValueWith(data []byte, opts ProtoOptions) (Array, error) {
+	return decodeProtoList(data, opts)
+}
+
+// MarshalProtoTimestamp encodes t in the protobuf binary wire format of the google.protobuf.Timestamp message
+func MarshalProtoTimestamp(t time.Time) ([]byte, error) {
+	seconds := t.Unix()
+
+	if seconds < minProtoTimestamp || seconds > maxProtoTimestamp {
+		return nil, fmt.Errorf("the time %s is outside of the range of a protobuf Timestamp", t.String())
+	}
+
+	var b []byte
+
+	if seconds != 0 {
+		b = appendProtoTag(b, 1, protoVarint)
+		b = appendUvarint(b, uint64(seconds))
+	}
+
+	if nanos := t.Nanosecond(); nanos != 0 {
+		b = appendProtoTag(b, 2, protoVarint)
+		b = appendUvarint(b, uint64(nanos))
+	}
+
+	return b, nil
+}
+
+// UnmarshalProtoTimestamp decodes the protobuf binary wire format of a google.protobuf.Timestamp message into a Time
+// Value in UTC
+func UnmarshalProtoTimestamp(data []byte) (Value, error) {
+	var seconds, nanos int64
+	r := protoReader{data: data}
+
+	for !r.done() {
+		field, wireType, err := r.tag()
+
+		if err != nil {
+			return Value{}, err
+		}
+
+		if (field == 1 || field == 2) && wireType == protoVarint {
+			u, err := r.varint()
+			if err != nil {
+				return Value{}, err
+			}
+			if field == 1 {
+				seconds = int64(u)
+			} else {
+				nanos = int64(int32(u))
+			}
+		} else if err := r.skip(wireType); err != nil {
+			return Value{}, err
+		}
+	}
+
+	if seconds < minProtoTimestamp || seconds > maxProtoTimestamp || nanos < 0 || nanos > 999999999 {
+		return Value{}, errors.New("the protobuf Timestamp is out of range")
+	}
+
+	return NewTimeValue(time.Unix(seconds, nanos).UTC()), nil
+}
+
+// FormatProtoTimestamp formats t using the proto3 JSON mapping for google.protobuf.Timestamp, which is RFC 3339 in UTC
+// with 0, 3, 6 or 9 fractional digits, e.g. 1972-01-01T10:00:20.021Z
+func FormatProtoTimestamp(t time.Time) string {
+	t = t.UTC()
+	s := t.Format("2006-01-02T15:04:05")
+	nanos := t.Nanosecond()
+
+	switch {
+	case nanos == 0:
+		return s + "Z"
+	case nanos%1000000 == 0:
+		return fmt.Sprintf("%s.%03dZ", s, nanos/1000000)
+	case nanos%1000 == 0:
+		return fmt.Sprintf("%s.%06dZ", s, nanos/1000)
+	}
+
+	return fmt.Sprintf("%s.%09dZ", s, nanos)
+}
+
+// ParseProtoTimestamp parses a string in the proto3 JSON format for google.protobuf.Timestamp into a Time Value. Any
+// offset is accepted, as the proto3 JSON mapping requires.
+func ParseProtoTimestamp(s string) (Value, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+
+	if err != nil || !strings.Contains(s, "T") {
+		return Value{}, fmt.Errorf("%q is not a protobuf Timestamp", s)
+	}
+
+	if seconds := t.Unix(); seconds < minProtoTimestamp || seconds > maxProtoTimestamp {
+		return Value{}, fmt.Errorf("%q is outside of the range of a protobuf Timestamp", s)
+	}
+
+	return NewTimeValue(t.UTC()), nil
+}
+
+// Private
+
+// protobuf wire types
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+// google.protobuf.Value field numbers
+const (
+	protoNullValue   = 1
+	protoNumberValue = 2
+	protoStringValue = 3
+	protoBoolValue   = 4
+	protoStructValue = 5
+	protoListValue   = 6
+)
+
+// maxProtoExactInt is the largest magnitude of an integer that a double holds exactly, 2^53
+const maxProtoExactInt = 1 << 53
+
+// the range of google.protobuf.Timestamp, 0001-01-01T00:00:00Z to 9999-12-31T23:59:59Z
+const (
+	minProtoTimestamp = -62135596800
+	maxProtoTimestamp = 253402300799
+)
+
+func appendUvarint(b []byte, u uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], u)
+	return append(b, buf[:n]...)
+}
+
+func appendProtoTag(b []byte, field int, wireType int) []byte {
+	return appendUvarint(b, uint64(field)<<3|uint64(wireType))
+}
+
+func appendProtoBytes(b []byte, field int, data []byte) []byte {
+	b = appendProtoTag(b, field, protoBytes)
+	b = appendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func appendProtoValue(b []byte, v Value) ([]byte, error) {
+	switch v.Type() {
+	case Null:
+		b = appendProtoTag(b, protoNullValue, protoVarint)
+		return append(b, 0), nil
+	case Bool:
+		b = appendProtoTag(b, protoBoolValue, protoVarint)
+		if v.Bool() {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	case Int:
+		if i := int64(v.Int()); i > maxProtoExactInt || i < -maxProtoExactInt {
+			return nil, fmt.Errorf("the Int %d cannot be encoded exactly as a protobuf number", i)
+		}
+		return appendProtoNumber(b, float64(v.Int())), nil
+	case Float:
+		return appendProtoNumber(b, v.Float()), nil
+	case String:
+		return appendProtoBytes(b, protoStringValue, []byte(v.String())), nil
+	case Bytes:
+		return appendProtoBytes(b, protoStringValue, []byte(defaultBytesEncoding.EncodeToString(v.Bytes()))), nil
+	case Time:
+		return appendProtoBytes(b, protoStringValue, []byte(FormatProtoTimestamp(v.Time()))), nil
+	case ObjectType:
+		sub, err := appendProtoStruct(nil, v.Object())
+		if err != nil {
+			return nil, err
+		}
+		return appendProtoBytes(b, protoStructValue, sub), nil
+	case ArrayType:
+		sub, err := appendProtoList(nil, v.Array())
+		if err != nil {
+			return nil, err
+		}
+		return appendProtoBytes(b, protoListValue, sub), nil
+	}
+
+	return nil, fmt.Errorf("the type %s cannot be encoded as a protobuf Value", v.Type().String())
+}
+
+func appendProtoNumber(b []byte, f float64) []byte {
+	b = appendProtoTag(b, protoNumberValue, protoFixed64)
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(f))
+	return append(b, buf[:]...)
+}
+
+func appendProtoStruct(b []byte, o Object) ([]byte, error) {
+	keys := make([]string, 0, len(o))
+
+	for key := range o {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		// each field is a map entry message with the key in field 1 and the value in field 2
+		entry := appendProtoBytes(nil, 1, []byte(key))
+		sub, err := appendProtoValue(nil, o[key])
+		if err != nil {
+			return nil, err
+		}
+		entry = appendProtoBytes(entry, 2, sub)
+		b = appendProtoBytes(b, 1, entry)
+	}
+
+	return b, nil
+}
+
+func appendProtoList(b []byte, a Array) ([]byte, error) {
+	for _, element := range a {
+		sub, err := appendProtoValue(nil, element)
+		if err != nil {
+			return nil, err
+		}
+		b = appendProtoBytes(b, 1, sub)
+	}
+
+	return b, nil
+}
+
+func decodeProtoValue(data []byte, opts ProtoOptions) (Value, error) {
+	var v Value
+	r := protoReader{data: data}
+
+	// when a oneof field appears more than once the last one wins
+	for !r.done() {
+		field, wireType, err := r.tag()
+
+		if err != nil {
+			return Value{}, err
+		}
+
+		switch {
+		case field == protoNullValue && wireType == protoVarint:
+			if _, err := r.varint(); err != nil {
+				return Value{}, err
+			}
+			v.SetNull()
+		case field == protoBoolValue && wireType == protoVarint:
+			u, err := r.varint()
+			if err != nil {
+				return Value{}, err
+			}
+			v.SetBool(u != 0)
+		case field == protoNumberValue && wireType == protoFixed64:
+			u, err := r.fixed64()
+			if err != nil {
+				return Value{}, err
+			}
+			v = protoNumber(math.Float64frombits(u))
+		case field == protoStringValue && wireType == protoBytes:
+			sub, err := r.bytes()
+			if err != nil {
+				return Value{}, err
+			}
+			v.SetString(string(sub))
+			if opts.ParseTimestamps {
+				if t, err := ParseProtoTimestamp(v.String()); err == nil {
+					v = t
+				}
+			}
+		case field == protoStructValue && wireType == protoBytes:
+			sub, err := r.bytes()
+			if err != nil {
+				return Value{}, err
+			}
+			o, err := decodeProtoStruct(sub, opts)
+			if err != nil {
+				return Value{}, err
+			}
+			v.SetObject(o)
+		case field == protoListValue && wireType == protoBytes:
+			sub, err := r.bytes()
+			if err != nil {
+				return Value{}, err
+			}
+			a, err := decodeProtoList(sub, opts)
+			if err != nil {
+				return Value{}, err
+			}
+			v.SetArray(a)
+		default:
+			if err := r.skip(wireType); err != nil {
+				return Value{}, err
+			}
+		}
+	}
+
+	return v, nil
+}
+
+// protoNumber converts a protobuf number to an Int if it holds an integral value that an int can represent
+func protoNumber(f float64) Value {
+	if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 && !math.IsInf(f, 0) {
+		return NewIntValue(int(f))
+	}
+
+	return NewFloatValue(f)
+}
+
+func decodeProtoStruct(data []byte, opts ProtoOptions) (Object, error) {
+	o := NewObject(3)
+	r := protoReader{data: data}
+
+	for !r.done() {
+		field, wireType, err := r.tag()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if field != 1 || wireType != protoBytes {
+			if err := r.skip(wireType); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		entry, err := r.bytes()
+
+		if err != nil {
+			return nil, err
+		}
+
+		key, v, err := decodeProtoMapEntry(entry, opts)
+
+		if err != nil {
+			return nil, err
+		}
+
+		o[key] = v
+	}
+
+	return o, nil
+}
+
+func decodeProtoMapEntry(data []byte, opts ProtoOptions) (string, Value, error) {
+	var key string
+	var v Value
+	r := protoReader{data: data}
+
+	for !r.done() {
+		field, wireType, err := r.tag()
+
+		if err != nil {
+			return "", Value{}, err
+		}
+
+		if (field == 1 || field == 2) && wireType == protoBytes {
+			sub, err := r.bytes()
+			if err != nil {
+				return "", Value{}, err
+			}
+			if field == 1 {
+				key = string(sub)
+			} else if v, err = decodeProtoValue(sub, opts); err != nil {
+				return "", Value{}, err
+			}
+		} else if err := r.skip(wireType); err != nil {
+			return "", Value{}, err
+		}
+	}
+
+	return key, v, nil
+}
+
+func decodeProtoList(data []byte, opts ProtoOptions) (Array, error) {
+	a := NewArray()
+	r := protoReader{data: data}
+
+	for !r.done() {
+		field, wireType, err := r.tag()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if field != 1 || wireType != protoBytes {
+			if err := r.skip(wireType); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		sub, err := r.bytes()
+
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := decodeProtoValue(sub, opts)
+
+		if err != nil {
+			return nil, err
+		}
+
+		a = append(a, v)
+	}
+
+	return a, nil
+}
+
+var errProtoTruncated = errors.New("the protobuf message is truncated")
+
+// protoReader reads the fields of a protobuf message
+type protoReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *protoReader) done() bool {
+	return r.pos >= len(r.data)
+}
+
+func (r *protoReader) varint() (uint64, error) {
+	u, n := binary.Uvarint(r.data[r.pos:])
+
+	if n <= 0 {
+		return 0, errProtoTruncated
+	}
+
+	r.pos += n
+	return u, nil
+}
+
+func (r *protoReader) tag() (field int, wireType int, err error) {
+	u, err := r.varint()
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if u>>3 == 0 {
+		return 0, 0, errors.New("the protobuf message contains an invalid field number")
+	}
+
+	return int(u >> 3), int(u & 7), nil
+}
+
+func (r *protoReader) fixed64() (uint64, error) {
+	if len(r.data)-r.pos < 8 {
+		return 0, errProtoTruncated
+	}
+
+	u := binary.LittleEndian.Uint64(r.data[r.pos:])
+	r.pos += 8
+	return u, nil
+}
+
+func (r *protoReader) bytes() ([]byte, error) {
+	length, err := r.varint()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if length > uint64(len(r.data)-r.pos) {
+		return nil, errProtoTruncated
+	}
+
+	b := r.data[r.pos : r.pos+int(length)]
+	r.pos += int(length)
+	return b, nil
+}
+
+// skip passes over a field that we do not recognize
+func (r *protoReader) skip(wireType int) error {
+	var err error
+
+	switch wireType {
+	case protoVarint:
+		_, err = r.varint()
+	case protoFixed64:
+		_, err = r.fixed64()
+	case protoBytes:
+		_, err = r.bytes()
+	case protoFixed32:
+		if len(r.data)-r.pos < 4 {
+			return errProtoTruncated
+		}
+		r.pos += 4
+	default:
+		return fmt.Errorf("the protobuf message contains the unsupported wire type %d", wireType)
+	}
+
+	return err
+}
@@ -0,0 +1,167 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/webern/tcore"
+)
+
+func TestMarshalProtoStruct(t *testing.T) {
+	// {"a": true} as a google.protobuf.Struct
+	want := []byte{0x0a, 0x07, 0x0a, 0x01, 'a', 0x12, 0x02, 0x20, 0x01}
+	got, err := MarshalProtoStruct(Object{"a": NewBoolValue(true)})
+
+	if msg, ok := tcore.TErr("MarshalProtoStruct", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("MarshalProtoStruct = % x, want % x", got, want)
+	}
+}
+
+func TestProtoValue_RoundTrip(t *testing.T) {
+	someTime := time.Date(2019, 5, 6, 17, 0, 0, 21000000, time.UTC)
+
+	testCases := []struct {
+		Input    Value
+		Expected Value
+	}{
+		{Input: NewValue(), Expected: NewValue()},
+		{Input: NewBoolValue(false), Expected: NewBoolValue(false)},
+		{Input: NewIntValue(-42), Expected: NewIntValue(-42)},
+		{Input: NewFloatValue(2.5), Expected: NewFloatValue(2.5)},
+		{Input: NewStringValue("hello"), Expected: NewStringValue("hello")},
+		{Input: NewTimeValue(someTime), Expected: NewStringValue("2019-05-06T17:00:00.021Z")},
+		{
+			Input: NewObjectValue(Object{
+				"list":   NewArrayValue(Array{NewIntValue(1), NewValue(), NewStringValue("x")}),
+				"nested": NewObjectValue(Object{"b": NewFloatValue(0.25)}),
+			}),
+			Expected: NewObjectValue(Object{
+				"list":   NewArrayValue(Array{NewIntValue(1), NewValue(), NewStringValue("x")}),
+				"nested": NewObjectValue(Object{"b": NewFloatValue(0.25)}),
+			}),
+		},
+	}
+
+	for tcix, tc := range testCases {
+		stm := fmt.Sprintf("test case %d: MarshalProtoValue(tc.Input)", tcix)
+		b, err := MarshalProtoValue(tc.Input)
+
+		if msg, ok := tcore.TErr(stm, err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		stm = fmt.Sprintf("test case %d: UnmarshalProtoValue(b)", tcix)
+		actual, err := UnmarshalProtoValue(b)
+
+		if msg, ok := tcore.TErr(stm, err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		stm = fmt.Sprintf("test case %d: actual.Equals(tc.Expected)", tcix)
+		if msg, ok := tcore.TAssertBool(stm, actual.Equals(tc.Expected), true); !ok {
+			t.Error(msg + fmt.Sprintf(" - '%v' != '%v'", actual, tc.Expected))
+		}
+	}
+
+	if _, err := UnmarshalProtoValue([]byte{0x1a, 0x05, 'a'}); err == nil {
+		t.Error("an error was expected for a truncated message")
+	}
+
+	if _, err := MarshalProtoValue(NewIntValue(1<<53 + 1)); err == nil {
+		t.Error("an error was expected for an Int that a double cannot hold exactly")
+	}
+
+	if _, err := MarshalProtoValue(NewIntValue(-1 << 53)); err != nil {
+		t.Errorf("-2^53 should encode exactly but got %v", err)
+	}
+}
+
+func TestUnmarshalProtoValueWith_ParseTimestamps(t *testing.T) {
+	someTime := time.Date(2019, 5, 6, 17, 0, 0, 21000000, time.UTC)
+	input := NewObjectValue(Object{
+		"when": NewTimeValue(someTime),
+		"list": NewArrayValue(Array{NewTimeValue(someTime), NewStringValue("not a time")}),
+	})
+	b, err := MarshalProtoValue(input)
+
+	if msg, ok := tcore.TErr("MarshalProtoValue(input)", err); !ok {
+		t.Fatal(msg)
+	}
+
+	actual, err := UnmarshalProtoValueWith(b, ProtoOptions{ParseTimestamps: true})
+
+	if msg, ok := tcore.TErr("UnmarshalProtoValueWith(b, opts)", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("actual.Equals(input)", actual.Equals(input), true); !ok {
+		t.Errorf("%s - got %v", msg, actual)
+	}
+
+	if got := actual.Object()["when"].Time(); !got.Equal(someTime) {
+		t.Errorf("expected the time to be kept to the nanosecond but got %v", got)
+	}
+
+	if b, err = MarshalProtoStruct(input.Object()); err != nil {
+		t.Fatal(err)
+	}
+
+	o, err := UnmarshalProtoStructWith(b, ProtoOptions{ParseTimestamps: true})
+
+	if msg, ok := tcore.TErr("UnmarshalProtoStructWith", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("o[when].IsTime()", o["when"].IsTime(), true); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestProtoTimestamp(t *testing.T) {
+	someTime := time.Date(1972, 1, 1, 10, 0, 20, 21000000, time.UTC)
+	b, err := MarshalProtoTimestamp(someTime)
+
+	if msg, ok := tcore.TErr("MarshalProtoTimestamp", err); !ok {
+		t.Fatal(msg)
+	}
+
+	v, err := UnmarshalProtoTimestamp(b)
+
+	if msg, ok := tcore.TErr("UnmarshalProtoTimestamp", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if !v.IsTime() || !v.Time().Equal(someTime) {
+		t.Errorf("UnmarshalProtoTimestamp = %v, want %v", v.Time(), someTime)
+	}
+
+	formatted := FormatProtoTimestamp(someTime)
+
+	if msg, ok := tcore.TAssertString("FormatProtoTimestamp", formatted, "1972-01-01T10:00:20.021Z"); !ok {
+		t.Error(msg)
+	}
+
+	v, err = ParseProtoTimestamp("1972-01-01T11:00:20.021+01:00")
+
+	if msg, ok := tcore.TErr("ParseProtoTimestamp", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if !v.Time().Equal(someTime) {
+		t.Errorf("ParseProtoTimestamp = %v, want %v", v.Time(), someTime)
+	}
+
+	if _, err = ParseProtoTimestamp("yesterday"); err == nil {
+		t.Error("an error was expected for a malformed timestamp")
+	}
+}
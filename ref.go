@@ -0,0 +1,165 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+// Ref refers to a location in a Value tree. It holds the root and a Path rather than the Value at the location, and
+// every call follows the Path from the root again, so edits made through a Ref are written through to the root even
+// when they change the length of a nested Array. A Ref stays valid as the tree changes, but it may come to refer to a
+// location that no longer exists, in which case its methods return a PathError.
+type Ref struct {
+	root *Value
+	path Path
+}
+
+// NewRef returns a Ref to root itself
+func NewRef(root *Value) Ref {
+	return Ref{root: root, path: Path{}}
+}
+
+// Key returns a Ref to the member of the referenced Object with the given key
+func (r Ref) Key(key string) Ref {
+	return Ref{root: r.root, path: r.path.Key(key)}
+}
+
+// Index returns a Ref to the element of the referenced Array at the given index
+func (r Ref) Index(index int) Ref {
+	return Ref{root: r.root, path: r.path.Index(index)}
+}
+
+// Parent returns a Ref to the container of the referenced location. It returns false for a Ref to the root.
+func (r Ref) Parent() (Ref, bool) {
+	if len(r.path) == 0 {
+		return r, false
+	}
+
+	return Ref{root: r.root, path: r.Path()[:len(r.path)-1]}, true
+}
+
+// Path returns the location of the Ref relative to the root
+func (r Ref) Path() Path {
+	path := make(Path, len(r.path))
+	copy(path, r.path)
+	return path
+}
+
+// Get returns the referenced Value
+func (r Ref) Get() (Value, error) {
+	return getPath(*r.root, r.path.String(), r.path)
+}
+
+// Exists returns true if the referenced location exists
+func (r Ref) Exists() bool {
+	_, err := r.Get()
+	return err == nil
+}
+
+// Set stores x at the referenced location. An Object member is added or replaced, but an Array element must already
+// exist, use Append or InsertAt on the Array to add one.
+func (r Ref) Set(x Value) error {
+	if len(r.path) == 0 {
+		*r.root = x
+		return nil
+	}
+
+	return r.update(func(parent *Value, last string) error {
+		return setChild(parent, last, x)
+	})
+}
+
+// Delete removes the referenced location from its container. Deleting the root sets it to Null.
+func (r Ref) Delete() error {
+	if len(r.path) == 0 {
+		r.root.SetNull()
+		return nil
+	}
+
+	return r.update(removeChild)
+}
+
+// Append adds elements to the end of the referenced Array
+func (r Ref) Append(elements ...Value) error {
+	return r.updateArray(func(a Array) (Array, error) {
+		return append(a, elements...), nil
+	})
+}
+
+// InsertAt inserts x into the referenced Array before the element at index. An index equal to the length of the Array
+// appends.
+func (r Ref) InsertAt(index int, x Value) error {
+	return r.updateArray(func(a Array) (Array, error) {
+		if index < 0 || index > len(a) {
+			return nil, ErrPathIndex
+		}
+		a = append(a, Value{})
+		copy(a[index+1:], a[index:])
+		a[index] = x
+		return a, nil
+	})
+}
+
+// RemoveAt removes the element at index from the referenced Array
+func (r Ref) RemoveAt(index int) error {
+	return r.updateArray(func(a Array) (Array, error) {
+		if index < 0 || index >= len(a) {
+			return nil, ErrPathIndex
+		}
+		return append(a[:index], a[index+1:]...), nil
+	})
+}
+
+// Private
+
+func (r Ref) update(fn func(parent *Value, last string) error) error {
+	return updatePath(r.root, r.path.String(), r.path, fn)
+}
+
+// updateArray replaces the referenced Array with the one that fn returns
+func (r Ref) updateArray(fn func(a Array) (Array, error)) error {
+	apply := func(node *Value) error {
+		if node.Type() != ArrayType {
+			return ErrPathTypeMismatch
+		}
+		a, err := fn(node.arr)
+		if err != nil {
+			return err
+		}
+		node.arr = a
+		return nil
+	}
+
+	if len(r.path) == 0 {
+		if err := apply(r.root); err != nil {
+			return &PathError{Path: "", Segment: "", Index: 0, Err: err}
+		}
+		return nil
+	}
+
+	return r.update(func(parent *Value, last string) error {
+		node, err := child(*parent, last)
+		if err != nil {
+			return err
+		}
+		if err = apply(&node); err != nil {
+			return err
+		}
+		return setChild(parent, last, node)
+	})
+}
+
+// setChild stores x as an existing Array element or as an Object member of parent
+func setChild(parent *Value, last string, x Value) error {
+	switch parent.Type() {
+	case ObjectType:
+		parent.obj[last] = x
+	case ArrayType:
+		i, ok := arrayIndex(last, len(parent.arr))
+		if !ok {
+			return ErrPathIndex
+		}
+		parent.arr[i] = x
+	default:
+		return ErrPathTypeMismatch
+	}
+
+	return nil
+}
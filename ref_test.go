@@ -0,0 +1,100 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"testing"
+
+	"github.com/webern/tcore"
+)
+
+func TestRef(t *testing.T) {
+	v := mustParseJSON(t, `{"a": {"list": [1, 2]}, "b": "x"}`)
+	list := NewRef(&v).Key("a").Key("list")
+
+	if err := list.Append(NewIntValue(3), NewIntValue(4)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := list.InsertAt(0, NewIntValue(0)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := list.RemoveAt(2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := list.Index(1).Set(NewObjectValue(Object{"n": NewIntValue(1)})); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := list.Index(1).Key("m").Set(NewBoolValue(true)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewRef(&v).Key("b").Delete(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := mustParseJSON(t, `{"a": {"list": [0, {"n": 1, "m": true}, 3, 4]}}`)
+
+	if msg, ok := tcore.TAssertBool("v.Equals(expected)", v.Equals(expected), true); !ok {
+		t.Errorf("%s - got %v", msg, v)
+	}
+
+	got, err := list.Index(3).Get()
+
+	if msg, ok := tcore.TErr("list.Index(3).Get()", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertInt("list.Index(3).Get()", got.Int(), 4); !ok {
+		t.Error(msg)
+	}
+
+	parent, ok := list.Index(3).Parent()
+
+	if msg, ok := tcore.TAssertBool("Parent() ok", ok, true); !ok {
+		t.Error(msg)
+	}
+
+	if msg, ok := tcore.TAssertString("parent.Path()", parent.Path().String(), "/a/list"); !ok {
+		t.Error(msg)
+	}
+
+	if _, ok = NewRef(&v).Parent(); ok {
+		t.Error("the root should have no parent")
+	}
+}
+
+func TestRef_Errors(t *testing.T) {
+	v := mustParseJSON(t, `{"a": [1], "s": "x"}`)
+	root := NewRef(&v)
+
+	testCases := []struct {
+		Name string
+		Err  error
+		Fn   func() error
+	}{
+		{Name: "Append to a String", Err: ErrPathTypeMismatch, Fn: func() error { return root.Key("s").Append(NewValue()) }},
+		{Name: "Append to a missing key", Err: ErrPathNotFound, Fn: func() error { return root.Key("z").Append(NewValue()) }},
+		{Name: "InsertAt past the end", Err: ErrPathIndex, Fn: func() error { return root.Key("a").InsertAt(2, NewValue()) }},
+		{Name: "RemoveAt past the end", Err: ErrPathIndex, Fn: func() error { return root.Key("a").RemoveAt(1) }},
+		{Name: "Set past the end", Err: ErrPathIndex, Fn: func() error { return root.Key("a").Index(1).Set(NewValue()) }},
+		{Name: "Append to the root Object", Err: ErrPathTypeMismatch, Fn: func() error { return root.Append(NewValue()) }},
+	}
+
+	for _, tc := range testCases {
+		err := tc.Fn()
+
+		if pathErr, ok := err.(*PathError); !ok || pathErr.Err != tc.Err {
+			t.Errorf("%s - expected a PathError holding '%v' but got '%v'", tc.Name, tc.Err, err)
+		}
+	}
+
+	expected := mustParseJSON(t, `{"a": [1], "s": "x"}`)
+
+	if msg, ok := tcore.TAssertBool("v is unchanged", v.Equals(expected), true); !ok {
+		t.Errorf("%s - got %v", msg, v)
+	}
+}
@@ -0,0 +1,271 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RowsToArray reads rows into an Array of Objects keyed by column name, stopping after limit rows if limit is greater
+// than zero. See ScanRows for how column values are converted. The caller remains responsible for closing rows.
+func RowsToArray(rows *sql.Rows, limit int) (Array, error) {
+	a := NewArray()
+	err := ScanRows(rows, limit, func(row Object) error {
+		a = append(a, NewObjectValue(row))
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// ScanRows calls fn with each row as an Object keyed by column name, stopping after limit rows if limit is greater
+// than zero, or when fn returns an error. The Type of each column is chosen from its database type name, or from its
+// scan type when the driver does not report a name: integer types become Int, floating point and decimal types become
+// Float, boolean types and BIT(1) become Bool, date and time types become Time, JSON types become Object or Array,
+// binary types and wider BIT(n) fields become Bytes, and everything else becomes String. NULL always becomes Null. A
+// driver value that Value.Scan cannot take is an error naming its column. The caller remains responsible for closing
+// rows.
+func ScanRows(rows *sql.Rows, limit int, fn func(row Object) error) error {
+	columns, err := rows.ColumnTypes()
+
+	if err != nil {
+		return err
+	}
+
+	types := make([]Type, len(columns))
+
+	for i, column := range columns {
+		types[i] = columnValueType(column)
+	}
+
+	raw := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+
+	for i := range raw {
+		dest[i] = &raw[i]
+	}
+
+	for count := 0; limit <= 0 || count < limit; count++ {
+		if !rows.Next() {
+			break
+		}
+
+		if err = rows.Scan(dest...); err != nil {
+			return err
+		}
+
+		row := NewObject(len(columns))
+
+		for i, column := range columns {
+			if row[column.Name()], err = columnValue(raw[i], types[i]); err != nil {
+				return fmt.Errorf("column %q: %s", column.Name(), err.Error())
+			}
+		}
+
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Private
+
+var databaseTypeNames = map[string]Type{
+	"INT":              Int,
+	"INT2":             Int,
+	"INT4":             Int,
+	"INT8":             Int,
+	"INTEGER":          Int,
+	"TINYINT":          Int,
+	"SMALLINT":         Int,
+	"MEDIUMINT":        Int,
+	"BIGINT":           Int,
+	"SERIAL":           Int,
+	"BIGSERIAL":        Int,
+	"YEAR":             Int,
+	"FLOAT":            Float,
+	"FLOAT4":           Float,
+	"FLOAT8":           Float,
+	"REAL":             Float,
+	"DOUBLE":           Float,
+	"DOUBLE PRECISION": Float,
+	"DECIMAL":          Float,
+	"NUMERIC":          Float,
+	"MONEY":            Float,
+	"BOOL":             Bool,
+	"BOOLEAN":          Bool,
+	"DATE":             Time,
+	"DATETIME":         Time,
+	"TIMESTAMP":        Time,
+	"TIMESTAMPTZ":      Time,
+	"TIME":             Time,
+	"TIMETZ":           Time,
+	"BLOB":             Bytes,
+	"TINYBLOB":         Bytes,
+	"MEDIUMBLOB":       Bytes,
+	"LONGBLOB":         Bytes,
+	"BYTEA":            Bytes,
+	"BINARY":           Bytes,
+	"VARBINARY":        Bytes,
+	"JSON":             ObjectType,
+	"JSONB":            ObjectType,
+}
+
+// columnValueType decides which Type a column maps to. ObjectType stands for JSON, which may also hold an array.
+func columnValueType(column *sql.ColumnType) Type {
+	name := strings.ToUpper(strings.TrimSpace(column.DatabaseTypeName()))
+
+	if i := strings.Index(name, "("); i >= 0 {
+		name = strings.TrimSpace(name[:i])
+	}
+
+	name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSuffix(name, " UNSIGNED"), "UNSIGNED "))
+
+	if t, ok := databaseTypeNames[name]; ok {
+		return t
+	}
+
+	// BIT(1) is a flag, while a wider BIT(n) is a bit field that MySQL delivers as []byte
+	if name == "BIT" {
+		if length, ok := column.Length(); ok && length == 1 {
+			return Bool
+		}
+		return Bytes
+	}
+
+	if name != "" || column.ScanType() == nil {
+		return String
+	}
+
+	scanType := column.ScanType()
+
+	for scanType.Kind() == reflect.Ptr {
+		scanType = scanType.Elem()
+	}
+
+	switch scanType {
+	case reflect.TypeOf(time.Time{}):
+		return Time
+	case reflect.TypeOf(sql.NullInt64{}):
+		return Int
+	case reflect.TypeOf(sql.NullFloat64{}):
+		return Float
+	case reflect.TypeOf(sql.NullBool{}):
+		return Bool
+	}
+
+	switch scanType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Int
+	case reflect.Float32, reflect.Float64:
+		return Float
+	case reflect.Bool:
+		return Bool
+	}
+
+	return String
+}
+
+// columnValue converts a value scanned from a column to the Type of the column. Values that cannot be converted are
+// given the Type that Value.Scan would choose, and a value that Value.Scan rejects is an error.
+func columnValue(raw interface{}, t Type) (Value, error) {
+	var v Value
+
+	if raw == nil {
+		return v, nil
+	}
+
+	text, isText := raw.(string)
+
+	if b, ok := raw.([]byte); ok {
+		text, isText = string(b), true
+	}
+
+	switch t {
+	case Int:
+		switch r := raw.(type) {
+		case int64:
+			return NewIntValue(int(r)), nil
+		case bool:
+			if r {
+				return NewIntValue(1), nil
+			}
+			return NewIntValue(0), nil
+		}
+		if i, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64); isText && err == nil {
+			return NewIntValue(int(i)), nil
+		}
+	case Float:
+		switch r := raw.(type) {
+		case float64:
+			return NewFloatValue(r), nil
+		case int64:
+			return NewFloatValue(float64(r)), nil
+		}
+		if f, err := strconv.ParseFloat(strings.TrimSpace(text), 64); isText && err == nil {
+			return NewFloatValue(f), nil
+		}
+	case Bool:
+		switch r := raw.(type) {
+		case bool:
+			return NewBoolValue(r), nil
+		case int64:
+			return NewBoolValue(r != 0), nil
+		case []byte:
+			if len(r) == 1 && r[0] <= 1 {
+				return NewBoolValue(r[0] == 1), nil
+			}
+		}
+		if b, err := strconv.ParseBool(strings.TrimSpace(text)); isText && err == nil {
+			return NewBoolValue(b), nil
+		}
+	case Time:
+		if isText {
+			if tm, ok := parseColumnTime(strings.TrimSpace(text)); ok {
+				return NewTimeValue(tm), nil
+			}
+		}
+	case String:
+		if isText {
+			return NewStringValue(text), nil
+		}
+	case Bytes:
+		if isText {
+			return NewBytesValue([]byte(text)), nil
+		}
+	}
+
+	err := v.Scan(raw)
+	return v, err
+}
+
+var columnTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+	"15:04:05.999999999",
+}
+
+func parseColumnTime(s string) (time.Time, bool) {
+	for _, layout := range columnTimeLayouts {
+		if tm, err := time.Parse(layout, s); err == nil {
+			return tm, true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,214 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/webern/tcore"
+)
+
+func init() {
+	someTime := time.Date(2019, 5, 6, 10, 0, 0, 0, time.UTC)
+
+	fakeTables["users"] = fakeTable{
+		columns: []string{"id", "score", "active", "created", "name", "settings", "nothing", "avatar"},
+		types:   []string{"BIGINT", "DECIMAL(10,2)", "BOOLEAN", "TIMESTAMP", "VARCHAR(32)", "JSONB", "TEXT", "BYTEA"},
+		rows: [][]driver.Value{
+			{int64(1), []byte("2.50"), true, someTime, []byte("alice"), []byte(`{"theme":"dark"}`), nil, []byte{0xff, 0}},
+			{[]byte("2"), float64(3), []byte("0"), []byte("2019-05-06 10:00:00"), "bob", []byte(`[1]`), nil, []byte(`[1]`)},
+			{int64(3), nil, false, nil, "[1]", nil, nil, nil},
+		},
+	}
+
+	fakeTables["bits"] = fakeTable{
+		columns: []string{"flag", "mask", "unsized"},
+		types:   []string{"BIT", "BIT", "BIT"},
+		lengths: []int64{1, 8},
+		rows: [][]driver.Value{
+			{[]byte{1}, []byte{0x0f}, []byte{0}},
+		},
+	}
+
+	fakeTables["unscannable"] = fakeTable{
+		columns: []string{"id", "odd"},
+		types:   []string{"BIGINT", "TEXT"},
+		rows: [][]driver.Value{
+			{int64(1), []string{"x"}},
+		},
+	}
+
+	fakeTables["untyped"] = fakeTable{
+		columns: []string{"n", "f", "b", "t"},
+		scanTypes: []reflect.Type{
+			reflect.TypeOf(int64(0)), reflect.TypeOf(float64(0)), reflect.TypeOf(false), reflect.TypeOf(time.Time{}),
+		},
+		rows: [][]driver.Value{
+			{[]byte("7"), []byte("1.5"), []byte("true"), []byte("2019-05-06T10:00:00Z")},
+		},
+	}
+}
+
+func TestRowsToArray(t *testing.T) {
+	db := openFakeDB(t)
+	defer db.Close()
+
+	someTime := time.Date(2019, 5, 6, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		Table    string
+		Limit    int
+		Expected Array
+	}{
+		{
+			Table: "users",
+			Limit: 0,
+			Expected: Array{
+				NewObjectValue(Object{
+					"id":       NewIntValue(1),
+					"score":    NewFloatValue(2.5),
+					"active":   NewBoolValue(true),
+					"created":  NewTimeValue(someTime),
+					"name":     NewStringValue("alice"),
+					"settings": NewObjectValue(Object{"theme": NewStringValue("dark")}),
+					"nothing":  NewValue(),
+					"avatar":   NewBytesValue([]byte{0xff, 0}),
+				}),
+				NewObjectValue(Object{
+					"id":       NewIntValue(2),
+					"score":    NewFloatValue(3),
+					"active":   NewBoolValue(false),
+					"created":  NewTimeValue(someTime),
+					"name":     NewStringValue("bob"),
+					"settings": NewArrayValue(Array{NewIntValue(1)}),
+					"nothing":  NewValue(),
+					"avatar":   NewBytesValue([]byte(`[1]`)),
+				}),
+				NewObjectValue(Object{
+					"id":       NewIntValue(3),
+					"score":    NewValue(),
+					"active":   NewBoolValue(false),
+					"created":  NewValue(),
+					"name":     NewStringValue("[1]"),
+					"settings": NewValue(),
+					"nothing":  NewValue(),
+					"avatar":   NewValue(),
+				}),
+			},
+		},
+		{
+			Table: "users",
+			Limit: 1,
+			Expected: Array{
+				NewObjectValue(Object{
+					"id":       NewIntValue(1),
+					"score":    NewFloatValue(2.5),
+					"active":   NewBoolValue(true),
+					"created":  NewTimeValue(someTime),
+					"name":     NewStringValue("alice"),
+					"settings": NewObjectValue(Object{"theme": NewStringValue("dark")}),
+					"nothing":  NewValue(),
+					"avatar":   NewBytesValue([]byte{0xff, 0}),
+				}),
+			},
+		},
+		{
+			Table: "bits",
+			Limit: 0,
+			Expected: Array{
+				NewObjectValue(Object{
+					"flag":    NewBoolValue(true),
+					"mask":    NewBytesValue([]byte{0x0f}),
+					"unsized": NewBytesValue([]byte{0}),
+				}),
+			},
+		},
+		{
+			Table: "untyped",
+			Limit: 10,
+			Expected: Array{
+				NewObjectValue(Object{
+					"n": NewIntValue(7),
+					"f": NewFloatValue(1.5),
+					"b": NewBoolValue(true),
+					"t": NewTimeValue(someTime),
+				}),
+			},
+		},
+	}
+
+	for tcix, tc := range testCases {
+		rows, err := db.Query(tc.Table)
+
+		if msg, ok := tcore.TErr(fmt.Sprintf("test case %d: db.Query", tcix), err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		stm := fmt.Sprintf("test case %d: RowsToArray(rows, %d)", tcix, tc.Limit)
+		actual, err := RowsToArray(rows, tc.Limit)
+		_ = rows.Close()
+
+		if msg, ok := tcore.TErr(stm, err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		if msg, ok := tcore.TAssertBool(stm, ArraysEqual(actual, tc.Expected), true); !ok {
+			t.Error(msg + fmt.Sprintf(" - '%v' != '%v'", actual, tc.Expected))
+		}
+	}
+}
+
+func TestScanRows_StopsOnError(t *testing.T) {
+	db := openFakeDB(t)
+	defer db.Close()
+
+	rows, err := db.Query("users")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	count := 0
+	stop := errors.New("stop")
+	err = ScanRows(rows, 0, func(row Object) error {
+		count++
+		return stop
+	})
+
+	if err != stop {
+		t.Errorf("ScanRows should return the callback error but returned %v", err)
+	}
+
+	if msg, ok := tcore.TAssertInt("count", count, 1); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestRowsToArray_ScanError(t *testing.T) {
+	db := openFakeDB(t)
+	defer db.Close()
+
+	rows, err := db.Query("unscannable")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	_, err = RowsToArray(rows, 0)
+
+	if err == nil || !strings.Contains(err.Error(), `column "odd"`) {
+		t.Errorf("expected an error naming the column but got '%v'", err)
+	}
+}
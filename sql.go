@@ -0,0 +1,160 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Scan implements sql.Scanner. NULL becomes Null, and integer, floating point, boolean, time and text columns become
+// Int, Float, Bool, Time and String. Text that holds a JSON object or array, e.g. from a JSON or JSONB column, becomes
+// an Object or Array. Drivers return both text and binary columns as []byte, so only a []byte that is not valid UTF-8
+// becomes Bytes, and a binary column holding valid UTF-8 comes back as a String. RowsToArray uses the column type
+// instead, and returns Bytes for every binary column.
+func (v *Value) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case nil:
+		v.SetNull()
+	case int64:
+		v.SetInt(int(s))
+	case float64:
+		v.SetFloat(s)
+	case bool:
+		v.SetBool(s)
+	case time.Time:
+		v.SetTime(s)
+	case []byte:
+		if !utf8.Valid(s) {
+			v.SetBytes(append([]byte{}, s...))
+			return nil
+		}
+		return v.scanText(string(s))
+	case string:
+		return v.scanText(s)
+	default:
+		return fmt.Errorf("cannot scan a %T into a Value", src)
+	}
+
+	return nil
+}
+
+// Value implements driver.Valuer. Null becomes NULL, scalars become the corresponding driver.Value type, Bytes
+// becomes a []byte, and Objects and Arrays become JSON text.
+func (v Value) Value() (driver.Value, error) {
+	switch v.Type() {
+	case Null:
+		return nil, nil
+	case Bool:
+		return v.Bool(), nil
+	case Int:
+		return int64(v.Int()), nil
+	case Float:
+		return v.Float(), nil
+	case String:
+		return v.String(), nil
+	case Bytes:
+		return v.Bytes(), nil
+	case Time:
+		return v.Time(), nil
+	}
+
+	b, err := json.Marshal(v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner for a column holding a JSON object. NULL becomes a nil Object.
+func (o *Object) Scan(src interface{}) error {
+	text, isNull, err := scanJSONText(src, "Object")
+
+	if err != nil || isNull {
+		*o = nil
+		return err
+	}
+
+	return o.UnmarshalJSON([]byte(text))
+}
+
+// Value implements driver.Valuer by returning the Object as JSON text. A nil Object becomes NULL.
+func (o Object) Value() (driver.Value, error) {
+	if o == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(o)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner for a column holding a JSON array. NULL becomes a nil Array.
+func (a *Array) Scan(src interface{}) error {
+	text, isNull, err := scanJSONText(src, "Array")
+
+	if err != nil || isNull {
+		*a = nil
+		return err
+	}
+
+	return a.UnmarshalJSON([]byte(text))
+}
+
+// Value implements driver.Valuer by returning the Array as JSON text. A nil Array becomes NULL.
+func (a Array) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(a)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Private
+
+// scanText scans a text column, which holds either a JSON object or array or an ordinary string
+func (v *Value) scanText(s string) error {
+	trimmed := strings.TrimSpace(s)
+	isObject := strings.HasPrefix(trimmed, "{") && strings.HasSuffix(trimmed, "}")
+	isArray := strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]")
+
+	if isObject || isArray {
+		var parsed Value
+		if err := json.Unmarshal([]byte(trimmed), &parsed); err == nil {
+			*v = parsed
+			return nil
+		}
+	}
+
+	v.SetString(s)
+	return nil
+}
+
+func scanJSONText(src interface{}, target string) (text string, isNull bool, err error) {
+	switch s := src.(type) {
+	case nil:
+		return "", true, nil
+	case []byte:
+		return string(s), false, nil
+	case string:
+		return s, false, nil
+	}
+
+	return "", false, fmt.Errorf("cannot scan a %T into an %s", src, target)
+}
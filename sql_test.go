@@ -0,0 +1,233 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/webern/tcore"
+)
+
+// fakeDriver is an in-process database/sql driver. The query "echo" returns a single row whose columns are the
+// arguments of the query, any other query returns the rows registered in fakeTables under that name.
+type fakeDriver struct{}
+
+type fakeTable struct {
+	columns   []string
+	types     []string
+	lengths   []int64
+	scanTypes []reflect.Type
+	rows      [][]driver.Value
+}
+
+var fakeTables = map[string]fakeTable{}
+
+func init() {
+	sql.Register("value-fake", fakeDriver{})
+}
+
+func openFakeDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("value-fake", "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return fakeConn{}, nil
+}
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeStmt{query: query}, nil
+}
+
+func (fakeConn) Close() error {
+	return nil
+}
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type fakeStmt struct {
+	query string
+}
+
+func (s fakeStmt) Close() error {
+	return nil
+}
+
+func (s fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("exec is not supported")
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if s.query == "echo" {
+		columns := make([]string, len(args))
+		for i := range args {
+			columns[i] = fmt.Sprintf("arg%d", i)
+		}
+		return &fakeRows{table: fakeTable{columns: columns, rows: [][]driver.Value{args}}}, nil
+	}
+
+	table, ok := fakeTables[s.query]
+
+	if !ok {
+		return nil, fmt.Errorf("no such table %q", s.query)
+	}
+
+	return &fakeRows{table: table}, nil
+}
+
+type fakeRows struct {
+	table fakeTable
+	next  int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.table.columns
+}
+
+func (r *fakeRows) ColumnTypeDatabaseTypeName(index int) string {
+	if index < len(r.table.types) {
+		return r.table.types[index]
+	}
+
+	return ""
+}
+
+func (r *fakeRows) ColumnTypeLength(index int) (int64, bool) {
+	if index < len(r.table.lengths) && r.table.lengths[index] > 0 {
+		return r.table.lengths[index], true
+	}
+
+	return 0, false
+}
+
+func (r *fakeRows) ColumnTypeScanType(index int) reflect.Type {
+	if index < len(r.table.scanTypes) {
+		return r.table.scanTypes[index]
+	}
+
+	return reflect.TypeOf(new(interface{})).Elem()
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.table.rows) {
+		return io.EOF
+	}
+
+	copy(dest, r.table.rows[r.next])
+	r.next++
+	return nil
+}
+
+func TestValue_ScanAndValue(t *testing.T) {
+	db := openFakeDB(t)
+	defer db.Close()
+
+	someTime := time.Date(2019, 5, 6, 10, 0, 0, 0, time.UTC)
+
+	testCases := []Value{
+		NewValue(),
+		NewBoolValue(true),
+		NewIntValue(42),
+		NewFloatValue(2.5),
+		NewStringValue("hello"),
+		NewStringValue("[not json"),
+		NewTimeValue(someTime),
+		NewObjectValue(Object{"a": NewArrayValue(Array{NewIntValue(1), NewStringValue("x")})}),
+		NewArrayValue(Array{NewBoolValue(false)}),
+		NewBytesValue([]byte{0xff, 0, 0xfe}),
+	}
+
+	for tcix, expected := range testCases {
+		var actual Value
+		stm := fmt.Sprintf("test case %d: db.QueryRow(\"echo\", expected).Scan(&actual)", tcix)
+		err := db.QueryRow("echo", expected).Scan(&actual)
+
+		if msg, ok := tcore.TErr(stm, err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		stm = fmt.Sprintf("test case %d: actual.Equals(expected)", tcix)
+		if msg, ok := tcore.TAssertBool(stm, actual.Equals(expected), true); !ok {
+			t.Error(msg + fmt.Sprintf(" - '%v' != '%v'", actual, expected))
+		}
+	}
+}
+
+func TestValue_ScanBytes(t *testing.T) {
+	db := openFakeDB(t)
+	defer db.Close()
+
+	var actual Value
+	err := db.QueryRow("echo", NewBytesValue([]byte("text"))).Scan(&actual)
+
+	if msg, ok := tcore.TErr("db.QueryRow(\"echo\", bytes).Scan(&actual)", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("actual.Equals(String)", actual.Equals(NewStringValue("text")), true); !ok {
+		t.Errorf("%s - valid UTF-8 is expected to come back as a String, got %v", msg, actual)
+	}
+}
+
+func TestObjectAndArray_ScanAndValue(t *testing.T) {
+	db := openFakeDB(t)
+	defer db.Close()
+
+	inObject := Object{"a": NewIntValue(1), "b": NewObjectValue(Object{"c": NewStringValue("d")})}
+	inArray := Array{NewIntValue(1), NewValue()}
+	var outObject Object
+	var outArray Array
+	err := db.QueryRow("echo", inObject, inArray).Scan(&outObject, &outArray)
+
+	if msg, ok := tcore.TErr("Scan", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("objectsEqual(outObject, inObject)", objectsEqual(outObject, inObject), true); !ok {
+		t.Error(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("ArraysEqual(outArray, inArray)", ArraysEqual(outArray, inArray), true); !ok {
+		t.Error(msg)
+	}
+
+	var nilObject Object
+	err = db.QueryRow("echo", nilObject).Scan(&outObject)
+
+	if msg, ok := tcore.TErr("Scan NULL", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if outObject != nil {
+		t.Errorf("scanning NULL should give a nil Object but got %v", outObject)
+	}
+
+	if err = db.QueryRow("echo", 5).Scan(&outArray); err == nil {
+		t.Error("an error was expected when scanning an integer into an Array")
+	}
+}
@@ -19,6 +19,7 @@ const (
 	Time                   // Time holds a time.Time value
 	ObjectType             // ObjectType holds an Object, which is a map[string]Value
 	ArrayType              // ArrayType holds a slice which is []Value
+	Bytes                  // Bytes holds a []byte value
 )
 
 const (
@@ -30,6 +31,7 @@ const (
 	StringTime    = "VALUE_TIME"
 	StringObject  = "VALUE_OBJECT"
 	StringArray   = "VALUE_ARRAY"
+	StringBytes   = "VALUE_BYTES"
 )
 
 var typeToString = map[Type]string{
@@ -41,6 +43,7 @@ var typeToString = map[Type]string{
 	Time:       StringTime,
 	ObjectType: StringObject,
 	ArrayType:  StringArray,
+	Bytes:      StringBytes,
 }
 
 var stringToType = map[string]Type{
@@ -52,6 +55,7 @@ var stringToType = map[string]Type{
 	StringTime:    Time,
 	StringObject:  ObjectType,
 	StringArray:   ArrayType,
+	StringBytes:   Bytes,
 }
 
 func (t Type) String() string {
@@ -3,6 +3,8 @@
 package value
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -11,6 +13,10 @@ import (
 	"time"
 )
 
+// defaultBytesEncoding is the base64 encoding of Bytes in MarshalJSON and CoerceTo. MarshalJSONWith,
+// CoerceToStringWith and CoerceToBytesWith take another, e.g. base64.URLEncoding or base64.RawStdEncoding.
+var defaultBytesEncoding = base64.StdEncoding
+
 // Value represents a weakly typed value, like we might find in JSON. Caution: this object is not trivially copyable.
 // You may use the Clone function to get a deep copy. If you use the assignment operator for copying, you will get a
 // shallow copy with multiple pointers to the same address. This is almost certainly not what you want.
@@ -22,6 +28,7 @@ type Value struct {
 	time *time.Time
 	obj  Object
 	arr  Array
+	bs   *[]byte
 }
 
 func (v *Value) Equals(other Value) bool {
@@ -49,6 +56,8 @@ func (v *Value) Equals(other Value) bool {
 		return ArraysEqual(v.Array(), other.Array())
 	case ObjectType:
 		return objectsEqual(v.Object(), other.Object())
+	case Bytes:
+		return bytes.Equal(v.Bytes(), other.Bytes())
 	}
 
 	return false
@@ -56,7 +65,7 @@ func (v *Value) Equals(other Value) bool {
 
 func (v *Value) SetType(iqType Type) {
 
-	if iqType < Null || iqType > ArrayType {
+	if iqType < Null || iqType > Bytes {
 		iqType = Null
 	}
 
@@ -70,6 +79,7 @@ func (v *Value) SetType(iqType Type) {
 			v.time = nil
 			v.obj = nil
 			v.arr = nil
+			v.bs = nil
 		}
 	case Bool:
 		{
@@ -81,6 +91,7 @@ func (v *Value) SetType(iqType Type) {
 			v.time = nil
 			v.obj = nil
 			v.arr = nil
+			v.bs = nil
 		}
 	case Int:
 		{
@@ -91,6 +102,7 @@ func (v *Value) SetType(iqType Type) {
 			v.time = nil
 			v.obj = nil
 			v.arr = nil
+			v.bs = nil
 		}
 	case Float:
 		{
@@ -102,6 +114,7 @@ func (v *Value) SetType(iqType Type) {
 			v.time = nil
 			v.obj = nil
 			v.arr = nil
+			v.bs = nil
 		}
 	case String:
 		{
@@ -112,6 +125,7 @@ func (v *Value) SetType(iqType Type) {
 			v.time = nil
 			v.obj = nil
 			v.arr = nil
+			v.bs = nil
 		}
 	case Time:
 		{
@@ -122,6 +136,7 @@ func (v *Value) SetType(iqType Type) {
 			v.time = new(time.Time)
 			v.obj = nil
 			v.arr = nil
+			v.bs = nil
 		}
 	case ObjectType:
 		{
@@ -132,6 +147,7 @@ func (v *Value) SetType(iqType Type) {
 			v.time = nil
 			v.obj = NewObject(3)
 			v.arr = nil
+			v.bs = nil
 		}
 	case ArrayType:
 		{
@@ -142,11 +158,25 @@ func (v *Value) SetType(iqType Type) {
 			v.time = nil
 			v.obj = nil
 			v.arr = NewArray()
+			v.bs = nil
+		}
+	case Bytes:
+		{
+			v.b = nil
+			v.i = nil
+			v.f = nil
+			v.str = nil
+			v.time = nil
+			v.obj = nil
+			v.arr = nil
+			v.bs = new([]byte)
+			*v.bs = []byte{}
 		}
 	}
 }
 
-// NewValueFromMystery makes a best effort to represent 'data' as a Value object
+// NewValueFromMystery makes a best effort to represent 'data' as a Value object. Types that it does not handle
+// directly, such as structs, are converted with Encode, so a nested time.Time stays a Time and []byte becomes Bytes.
 func NewValueFromMystery(data interface{}) (v Value, err error) {
 
 	switch data.(type) {
@@ -260,21 +290,8 @@ func NewValueFromMystery(data interface{}) (v Value, err error) {
 		return v, nil
 	}
 
-	// maybe it is a struct, maybe we can marshal and unmarshal it
-	b, err := json.Marshal(data)
-
-	if err != nil {
-		return v, err
-	}
-
-	err = json.Unmarshal(b, &v)
-
-	if err != nil {
-		v.SetNull()
-		return v, err
-	}
-
-	return v, nil
+	// maybe it is a struct, a typed slice or map, or another kind of number
+	return Encode(data)
 }
 
 func (v Value) Type() Type {
@@ -293,6 +310,8 @@ func (v Value) Type() Type {
 		return ObjectType
 	} else if v.arr != nil {
 		return ArrayType
+	} else if v.bs != nil {
+		return Bytes
 	}
 
 	return Null
@@ -338,6 +357,15 @@ func (v Value) TryTime() (value time.Time, err error) {
 	return *v.time, nil
 }
 
+// TryBytes returns the bytes held by a Bytes Value, or an error for any other Type
+func (v Value) TryBytes() (value []byte, err error) {
+	if v.bs == nil {
+		return nil, fmt.Errorf("TryBytes was called but the type is %s", v.Type().String())
+	}
+
+	return *v.bs, nil
+}
+
 func (v Value) Time() time.Time {
 	if v.time == nil {
 		return time.Time{}
@@ -391,6 +419,15 @@ func (v *Value) SetTime(value time.Time) {
 	*v.time = value
 }
 
+// SetBytes makes v a Bytes Value holding value, which is not copied
+func (v *Value) SetBytes(value []byte) {
+	v.SetType(Bytes)
+
+	if value != nil {
+		*v.bs = value
+	}
+}
+
 func (v *Value) SetObject(value Object) {
 	v.SetType(ObjectType)
 	v.obj = value
@@ -401,6 +438,21 @@ func (v *Value) SetArray(value Array) {
 	v.arr = value
 }
 
+// JSONOptions controls MarshalJSONWith
+type JSONOptions struct {
+	// BytesEncoding is the base64 encoding of Bytes, base64.StdEncoding when nil
+	BytesEncoding *base64.Encoding
+}
+
+// MarshalJSONWith is MarshalJSON with options
+func (v Value) MarshalJSONWith(opts JSONOptions) ([]byte, error) {
+	if opts.BytesEncoding == nil {
+		return json.Marshal(v)
+	}
+
+	return json.Marshal(bytesToStrings(v, opts.BytesEncoding))
+}
+
 func (v Value) MarshalJSON() ([]byte, error) {
 
 	t := v.Type()
@@ -454,6 +506,10 @@ func (v Value) MarshalJSON() ([]byte, error) {
 
 			return json.Marshal(base)
 		}
+	case Bytes:
+		{
+			return json.Marshal(defaultBytesEncoding.EncodeToString(v.Bytes()))
+		}
 	default:
 		{
 			return json.Marshal(nil)
@@ -550,6 +606,10 @@ func (v Value) Clone() Value {
 		{
 			newVal.SetArray(v.Array().Clone())
 		}
+	case Bytes:
+		{
+			newVal.SetBytes(append([]byte{}, v.Bytes()...))
+		}
 	}
 
 	return newVal
@@ -590,6 +650,13 @@ func NewFloatValue(f float64) Value {
 	return val
 }
 
+// NewBytesValue returns a Bytes Value holding b, which is not copied
+func NewBytesValue(b []byte) Value {
+	var val Value
+	val.SetBytes(b)
+	return val
+}
+
 func NewArrayValue(a Array) Value {
 	var val Value
 	val.SetArray(a)
@@ -628,6 +695,11 @@ func (v Value) Bool() bool {
 	return o
 }
 
+func (v Value) Bytes() []byte {
+	o, _ := v.TryBytes()
+	return o
+}
+
 func (v Value) IsNull() bool {
 	return v.Type() == Null
 }
@@ -652,6 +724,10 @@ func (v Value) IsTime() bool {
 	return v.Type() == Time
 }
 
+func (v Value) IsBytes() bool {
+	return v.Type() == Bytes
+}
+
 func (v Value) CoerceTo(t Type) (newValue Value, ok bool) {
 	if t == String {
 		return v.CoerceToString()
@@ -661,6 +737,8 @@ func (v Value) CoerceTo(t Type) (newValue Value, ok bool) {
 		return v.CoearceToFloat()
 	} else if t == Bool {
 		return v.CoerceToBool()
+	} else if t == Bytes {
+		return v.CoerceToBytes()
 	} else if t == Null {
 		return Value{}, true
 	}
@@ -679,7 +757,7 @@ func (v Value) CoerceToInt() (newValue Value, ok bool) {
 		}
 	case Bool:
 		{
-			if newValue.Bool() {
+			if v.Bool() {
 				newValue.SetInt(1)
 				return newValue, ok
 			} else {
@@ -735,7 +813,7 @@ func (v Value) CoearceToFloat() (newValue Value, ok bool) {
 		}
 	case Bool:
 		{
-			if newValue.Bool() {
+			if v.Bool() {
 				newValue.SetFloat(1.0)
 				return newValue, ok
 			} else {
@@ -787,6 +865,8 @@ func (v Value) CoerceToString() (newValue Value, ok bool) {
 	ok = true
 	if t == String {
 		return v.Clone(), ok
+	} else if t == Bytes {
+		return NewStringValue(defaultBytesEncoding.EncodeToString(v.Bytes())), ok
 	}
 
 	js, err := json.Marshal(v)
@@ -870,3 +950,73 @@ func (v Value) CoerceToBool() (newValue Value, ok bool) {
 	newValue.SetBool(false)
 	return newValue, false
 }
+
+// CoerceToStringWith is CoerceToString, but it encodes Bytes, including Bytes inside an Object or Array, with enc
+func (v Value) CoerceToStringWith(enc *base64.Encoding) (newValue Value, ok bool) {
+	return bytesToStrings(v, enc).CoerceToString()
+}
+
+// CoerceToBytes decodes a String with base64.StdEncoding. Null becomes empty Bytes.
+func (v Value) CoerceToBytes() (newValue Value, ok bool) {
+	return v.CoerceToBytesWith(defaultBytesEncoding)
+}
+
+// CoerceToBytesWith is CoerceToBytes, but it decodes a String with enc
+func (v Value) CoerceToBytesWith(enc *base64.Encoding) (newValue Value, ok bool) {
+	t := v.Type()
+	ok = true
+	switch t {
+	case Null:
+		{
+			newValue.SetBytes(nil)
+			return newValue, ok
+		}
+	case Bytes:
+		{
+			return v.Clone(), ok
+		}
+	case String:
+		{
+			b, err := enc.DecodeString(v.String())
+
+			if err != nil {
+				newValue.SetBytes(nil)
+				return newValue, false
+			}
+
+			newValue.SetBytes(b)
+			return newValue, ok
+		}
+	}
+
+	newValue.SetBytes(nil)
+	return newValue, false
+}
+
+// bytesToStrings returns v with each Bytes replaced by a String in enc. Everything else is shared with v.
+func bytesToStrings(v Value, enc *base64.Encoding) Value {
+	switch v.Type() {
+	case Bytes:
+		return NewStringValue(enc.EncodeToString(v.Bytes()))
+	case ObjectType:
+		if v.Object() == nil {
+			return v
+		}
+		o := NewObject(len(v.Object()))
+		for key, member := range v.Object() {
+			o[key] = bytesToStrings(member, enc)
+		}
+		return NewObjectValue(o)
+	case ArrayType:
+		if v.Array() == nil {
+			return v
+		}
+		a := make(Array, len(v.Array()))
+		for i, element := range v.Array() {
+			a[i] = bytesToStrings(element, enc)
+		}
+		return NewArrayValue(a)
+	}
+
+	return v
+}
@@ -3,6 +3,9 @@
 package value
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -102,6 +105,41 @@ func TestNewValueFromMystery(t *testing.T) {
 			Expected:      NewArrayValue(Array{NewIntValue(1), NewIntValue(2)}),
 			IsErrExpected: false,
 		},
+		{
+			Mystery: struct {
+				When time.Time
+				Size uint64
+			}{When: someTime, Size: 6},
+			Expected: NewObjectValue(Object{
+				"When": NewTimeValue(someTime),
+				"Size": NewIntValue(6),
+			}),
+			IsErrExpected: false,
+		},
+		{
+			Mystery:       []byte("hi"),
+			Expected:      NewBytesValue([]byte("hi")),
+			IsErrExpected: false,
+		},
+		{
+			Mystery: struct {
+				Name    string `json:"name"`
+				Skipped string `json:"-"`
+				hidden  string
+			}{Name: "n", Skipped: "s", hidden: "h"},
+			Expected:      NewObjectValue(Object{"name": NewStringValue("n")}),
+			IsErrExpected: false,
+		},
+		{
+			Mystery:       []interface{}{json.RawMessage(`{"raw": true}`)},
+			Expected:      NewArrayValue(Array{NewObjectValue(Object{"raw": NewBoolValue(true)})}),
+			IsErrExpected: false,
+		},
+		{
+			Mystery:       map[int]string{1: "one"},
+			Expected:      NewObjectValue(Object{"1": NewStringValue("one")}),
+			IsErrExpected: false,
+		},
 	}
 
 	for tcix, tc := range testCases {
@@ -125,3 +163,155 @@ func TestNewValueFromMystery(t *testing.T) {
 		}
 	}
 }
+
+func TestValue_CoerceBool(t *testing.T) {
+	testCases := []struct {
+		Input    Value
+		To       Type
+		Expected Value
+	}{
+		{Input: NewBoolValue(true), To: Int, Expected: NewIntValue(1)},
+		{Input: NewBoolValue(false), To: Int, Expected: NewIntValue(0)},
+		{Input: NewBoolValue(true), To: Float, Expected: NewFloatValue(1)},
+		{Input: NewBoolValue(false), To: Float, Expected: NewFloatValue(0)},
+	}
+
+	for tcix, tc := range testCases {
+		stm := fmt.Sprintf("test case %d: tc.Input.CoerceTo(%s)", tcix, tc.To.String())
+		actual, ok := tc.Input.CoerceTo(tc.To)
+
+		if msg, ok := tcore.TAssertBool(stm+" ok", ok, true); !ok {
+			t.Error(msg)
+		}
+
+		if msg, ok := tcore.TAssertBool(stm, actual.Equals(tc.Expected), true); !ok {
+			t.Errorf("%s - got %v", msg, actual)
+		}
+	}
+}
+
+func TestValue_Bytes(t *testing.T) {
+	data := []byte{0xfb, 0xff, 0x00}
+	v := NewBytesValue(data)
+
+	if msg, ok := tcore.TAssertString("v.Type()", v.Type().String(), StringBytes); !ok {
+		t.Error(msg)
+	}
+
+	got, err := v.TryBytes()
+
+	if msg, ok := tcore.TErr("v.TryBytes()", err); !ok {
+		t.Error(msg)
+	} else if !bytes.Equal(got, data) {
+		t.Errorf("v.TryBytes() = % x, want % x", got, data)
+	}
+
+	if _, err = NewStringValue("x").TryBytes(); err == nil {
+		t.Error("an error was expected from TryBytes on a String")
+	}
+
+	clone := v.Clone()
+	data[0] = 0
+
+	if msg, ok := tcore.TAssertBool("clone.Equals(v)", clone.Equals(v), false); !ok {
+		t.Error(msg)
+	}
+
+	original := NewBytesValue([]byte{0xfb, 0xff, 0x00})
+
+	if msg, ok := tcore.TAssertBool("clone.Equals(original)", clone.Equals(original), true); !ok {
+		t.Error(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("Bytes.Equals(String)", v.Equals(NewStringValue(string(data))), false); !ok {
+		t.Error(msg)
+	}
+
+	var empty Value
+	empty.SetBytes(nil)
+
+	if msg, ok := tcore.TAssertBool("empty.IsBytes()", empty.IsBytes(), true); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestValue_Bytes_JSON(t *testing.T) {
+	v := NewObjectValue(Object{"b": NewArrayValue(Array{NewBytesValue([]byte{0xfb, 0xff})})})
+
+	testCases := []struct {
+		Encoding *base64.Encoding
+		Expected string
+	}{
+		{Encoding: nil, Expected: `{"b":["+/8="]}`},
+		{Encoding: base64.URLEncoding, Expected: `{"b":["-_8="]}`},
+		{Encoding: base64.RawStdEncoding, Expected: `{"b":["+/8"]}`},
+		{Encoding: base64.RawURLEncoding, Expected: `{"b":["-_8"]}`},
+	}
+
+	for tcix, tc := range testCases {
+		b, err := v.MarshalJSONWith(JSONOptions{BytesEncoding: tc.Encoding})
+		stm := fmt.Sprintf("test case %d: v.MarshalJSONWith(opts)", tcix)
+
+		if msg, ok := tcore.TErr(stm, err); !ok {
+			t.Error(msg)
+			continue
+		}
+
+		if msg, ok := tcore.TAssertString(stm, string(b), tc.Expected); !ok {
+			t.Error(msg)
+		}
+	}
+
+	b, err := json.Marshal(v)
+
+	if msg, ok := tcore.TErr("json.Marshal(v)", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertString("json.Marshal(v)", string(b), `{"b":["+/8="]}`); !ok {
+		t.Error(msg)
+	}
+
+	if msg, ok := tcore.TAssertBool("v is unchanged", v.Object()["b"].Array()[0].IsBytes(), true); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestValue_Bytes_Coerce(t *testing.T) {
+	v := NewBytesValue([]byte("hi"))
+	s, ok := v.CoerceTo(String)
+
+	if msg, ok := tcore.TAssertBool("v.CoerceTo(String) ok", ok, true); !ok {
+		t.Error(msg)
+	}
+
+	if msg, ok := tcore.TAssertString("v.CoerceTo(String)", s.String(), "aGk="); !ok {
+		t.Error(msg)
+	}
+
+	back, ok := s.CoerceTo(Bytes)
+
+	if msg, ok := tcore.TAssertBool("back.Equals(v)", ok && back.Equals(v), true); !ok {
+		t.Error(msg)
+	}
+
+	s, _ = v.CoerceToStringWith(base64.RawURLEncoding)
+
+	if msg, ok := tcore.TAssertString("v.CoerceToStringWith(RawURLEncoding)", s.String(), "aGk"); !ok {
+		t.Error(msg)
+	}
+
+	back, ok = s.CoerceToBytesWith(base64.RawURLEncoding)
+
+	if msg, ok := tcore.TAssertBool("back.Equals(v) with RawURLEncoding", ok && back.Equals(v), true); !ok {
+		t.Error(msg)
+	}
+
+	if _, ok = NewStringValue("not base64!").CoerceTo(Bytes); ok {
+		t.Error("coercing a string that is not base64 should fail")
+	}
+
+	if _, ok = NewIntValue(1).CoerceTo(Bytes); ok {
+		t.Error("coercing an Int to Bytes should fail")
+	}
+}
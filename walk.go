@@ -0,0 +1,107 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import "errors"
+
+// These errors may be returned by a walk function to control the walk. Walk itself never returns them.
+var (
+	SkipSubtree = errors.New("skip the children of this node")
+	StopWalk    = errors.New("stop the walk")
+)
+
+// WalkFunc is called by Walk for each node. path locates the node and parent is the Object or Array holding it, or
+// Null for the root. Each node is visited twice, first with post false, then with post true after its children.
+//
+// Returning SkipSubtree from the first visit skips the node's children and its second visit, and from the second visit
+// it has no effect. Returning StopWalk from either visit ends the walk without error. Any other error ends the walk
+// and is returned by Walk.
+type WalkFunc func(path Path, v Value, parent Value, post bool) error
+
+// Walk visits every node of the tree rooted at v in depth first order, calling fn before and after the children of
+// each node. Object members are visited in sorted key order.
+func Walk(v Value, fn WalkFunc) error {
+	return ignoreStopWalk(walkValue(Path{}, v, Value{}, fn))
+}
+
+// WalkInPlaceFunc is the WalkFunc of WalkInPlace. Assigning to *v replaces the node in the tree. A replacement made in
+// the first visit is the node whose children are then walked. parent should only be read, and is nil for the root.
+type WalkInPlaceFunc func(path Path, v *Value, parent *Value, post bool) error
+
+// WalkInPlace is Walk for modifying a tree. The walk function receives a pointer to each node, through which the node
+// can be replaced, and the changes are written back so that they are visible through root.
+func WalkInPlace(root *Value, fn WalkInPlaceFunc) error {
+	return ignoreStopWalk(walkInPlace(Path{}, root, nil, fn))
+}
+
+// Private
+
+func ignoreStopWalk(err error) error {
+	if err == StopWalk {
+		return nil
+	}
+
+	return err
+}
+
+func walkValue(path Path, v Value, parent Value, fn WalkFunc) error {
+	if err := fn(path, v, parent, false); err == SkipSubtree {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	switch v.Type() {
+	case ObjectType:
+		o := v.Object()
+		for _, key := range sortedKeys(o) {
+			if err := walkValue(path.Key(key), o[key], v, fn); err != nil {
+				return err
+			}
+		}
+	case ArrayType:
+		for i, element := range v.Array() {
+			if err := walkValue(path.Index(i), element, v, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := fn(path, v, parent, true); err != SkipSubtree {
+		return err
+	}
+
+	return nil
+}
+
+func walkInPlace(path Path, v *Value, parent *Value, fn WalkInPlaceFunc) error {
+	if err := fn(path, v, parent, false); err == SkipSubtree {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	switch v.Type() {
+	case ObjectType:
+		for _, key := range sortedKeys(v.obj) {
+			member := v.obj[key]
+			err := walkInPlace(path.Key(key), &member, v, fn)
+			v.obj[key] = member
+			if err != nil {
+				return err
+			}
+		}
+	case ArrayType:
+		for i := range v.arr {
+			if err := walkInPlace(path.Index(i), &v.arr[i], v, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := fn(path, v, parent, true); err != SkipSubtree {
+		return err
+	}
+
+	return nil
+}
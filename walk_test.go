@@ -0,0 +1,153 @@
+// go-value, Copyright (c) 2019-present by Matthew James Briggs
+
+package value
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/webern/tcore"
+)
+
+func TestWalk(t *testing.T) {
+	v := mustParseJSON(t, `{"b": [1, {"c": true}], "a": "x"}`)
+	var visits []string
+
+	err := Walk(v, func(path Path, node Value, parent Value, post bool) error {
+		order := "pre"
+		if post {
+			order = "post"
+		}
+		visits = append(visits, fmt.Sprintf("%s %s %s", order, path.String(), parent.Type().String()))
+		return nil
+	})
+
+	if msg, ok := tcore.TErr("Walk", err); !ok {
+		t.Fatal(msg)
+	}
+
+	expected := []string{
+		"pre  VALUE_NULL",
+		"pre /a VALUE_OBJECT",
+		"post /a VALUE_OBJECT",
+		"pre /b VALUE_OBJECT",
+		"pre /b/0 VALUE_ARRAY",
+		"post /b/0 VALUE_ARRAY",
+		"pre /b/1 VALUE_ARRAY",
+		"pre /b/1/c VALUE_OBJECT",
+		"post /b/1/c VALUE_OBJECT",
+		"post /b/1 VALUE_ARRAY",
+		"post /b VALUE_OBJECT",
+		"post  VALUE_NULL",
+	}
+
+	if msg, ok := tcore.TAssertString("visits", strings.Join(visits, "\n"), strings.Join(expected, "\n")); !ok {
+		t.Error(msg)
+	}
+}
+
+func TestWalk_SkipAndStop(t *testing.T) {
+	v := mustParseJSON(t, `{"a": {"x": 1}, "b": {"y": 2}, "c": 3}`)
+	var visited []string
+
+	err := Walk(v, func(path Path, node Value, parent Value, post bool) error {
+		if post {
+			return nil
+		}
+		visited = append(visited, path.String())
+		switch path.String() {
+		case "/a":
+			return SkipSubtree
+		case "/b/y":
+			return StopWalk
+		}
+		return nil
+	})
+
+	if msg, ok := tcore.TErr("Walk", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertString("visited", strings.Join(visited, ","), ",/a,/b,/b/y"); !ok {
+		t.Error(msg)
+	}
+
+	visited = nil
+	err = Walk(v, func(path Path, node Value, parent Value, post bool) error {
+		if !post {
+			return nil
+		}
+		visited = append(visited, path.String())
+		switch path.String() {
+		case "/a":
+			return SkipSubtree
+		case "/b":
+			return StopWalk
+		}
+		return nil
+	})
+
+	if msg, ok := tcore.TErr("Walk with post visit control errors", err); !ok {
+		t.Fatal(msg)
+	}
+
+	if msg, ok := tcore.TAssertString("visited", strings.Join(visited, ","), "/a/x,/a,/b/y,/b"); !ok {
+		t.Error(msg)
+	}
+
+	root := v.Clone()
+	err = WalkInPlace(&root, func(path Path, node *Value, parent *Value, post bool) error {
+		if post && path.String() == "/a" {
+			return SkipSubtree
+		}
+		if post && path.String() == "/c" {
+			return StopWalk
+		}
+		return nil
+	})
+
+	if msg, ok := tcore.TErr("WalkInPlace with post visit control errors", err); !ok {
+		t.Fatal(msg)
+	}
+
+	failure := errors.New("failure")
+	err = Walk(v, func(path Path, node Value, parent Value, post bool) error {
+		if path.String() == "/c" {
+			return failure
+		}
+		return nil
+	})
+
+	if err != failure {
+		t.Errorf("expected the walk function's error but got '%v'", err)
+	}
+}
+
+func TestWalkInPlace(t *testing.T) {
+	v := mustParseJSON(t, `{"a": [1, 2, {"b": 3}], "c": "secret", "d": [[4]]}`)
+
+	err := WalkInPlace(&v, func(path Path, node *Value, parent *Value, post bool) error {
+		switch {
+		case post:
+		case node.IsInt():
+			node.SetInt(node.Int() * 10)
+		case path.String() == "/c":
+			node.SetString("***")
+		case path.String() == "/d":
+			node.SetArray(append(node.Array(), NewIntValue(5)))
+		}
+		return nil
+	})
+
+	if msg, ok := tcore.TErr("WalkInPlace", err); !ok {
+		t.Fatal(msg)
+	}
+
+	expected := mustParseJSON(t, `{"a": [10, 20, {"b": 30}], "c": "***", "d": [[40], 50]}`)
+
+	if msg, ok := tcore.TAssertBool("v.Equals(expected)", v.Equals(expected), true); !ok {
+		t.Errorf("%s - got %v", msg, v)
+	}
+}